@@ -0,0 +1,579 @@
+package arrowops
+
+import (
+	"fmt"
+
+	"github.com/alekLukanen/errs"
+	"github.com/apache/arrow/go/v17/arrow"
+	"github.com/apache/arrow/go/v17/arrow/array"
+	"github.com/apache/arrow/go/v17/arrow/memory"
+)
+
+type aggregateFunction int
+
+const (
+	sumAggregate aggregateFunction = iota
+	countAggregate
+	minAggregate
+	maxAggregate
+	meanAggregate
+	countDistinctAggregate
+)
+
+func (f aggregateFunction) String() string {
+	switch f {
+	case sumAggregate:
+		return "sum"
+	case countAggregate:
+		return "count"
+	case minAggregate:
+		return "min"
+	case maxAggregate:
+		return "max"
+	case meanAggregate:
+		return "mean"
+	default:
+		return "count_distinct"
+	}
+}
+
+// the column name used by Count to count every row
+const CountAllColumns = "*"
+
+/*
+An aggregate function applied to a column of each group. The result column
+is named after the function and the column, for example "sum(amount)",
+unless a name is provided with As. Null values are ignored by every
+aggregate function.
+*/
+type Aggregation struct {
+	function aggregateFunction
+	column   string
+	name     string
+}
+
+/*
+The sum of the values in a numeric column. Signed integers are summed as INT64,
+unsigned integers as UINT64 and floats as FLOAT64. The sum is null when the group
+has no values.
+*/
+func Sum(column string) Aggregation {
+	return Aggregation{function: sumAggregate, column: column}
+}
+
+/*
+The number of values in the column as an INT64, or the number of rows when the
+column is CountAllColumns.
+*/
+func Count(column string) Aggregation {
+	return Aggregation{function: countAggregate, column: column}
+}
+
+/*
+The smallest value in the column, using the same ordering as SortRecordIndices.
+The minimum is null when the group has no values.
+*/
+func Min(column string) Aggregation {
+	return Aggregation{function: minAggregate, column: column}
+}
+
+/*
+The largest value in the column, using the same ordering as SortRecordIndices.
+The maximum is null when the group has no values.
+*/
+func Max(column string) Aggregation {
+	return Aggregation{function: maxAggregate, column: column}
+}
+
+/*
+The mean of the values in a numeric column as a FLOAT64. The mean is null when
+the group has no values.
+*/
+func Mean(column string) Aggregation {
+	return Aggregation{function: meanAggregate, column: column}
+}
+
+/*
+The number of distinct values in the column as an INT64.
+*/
+func CountDistinct(column string) Aggregation {
+	return Aggregation{function: countDistinctAggregate, column: column}
+}
+
+/*
+Returns a copy of the aggregation with the result column named name.
+*/
+func (a Aggregation) As(name string) Aggregation {
+	a.name = name
+	return a
+}
+
+func (a Aggregation) Name() string {
+	if a.name != "" {
+		return a.name
+	}
+	return fmt.Sprintf("%s(%s)", a.function, a.column)
+}
+
+/*
+Accumulates the values of a column for a set of groups. Groups are numbered from
+zero and the state grows with resize. flush builds the results of the first
+numGroups groups and moves the state of the remaining groups to the front, so
+groups can be emitted while later groups are still being accumulated.
+*/
+type aggregator interface {
+	dataType() arrow.DataType
+	resize(numGroups int)
+	// arr is nil when counting all rows
+	update(arr arrow.Array, groupIDs []uint32)
+	flush(mem *memory.GoAllocator, numGroups int) (arrow.Array, error)
+	release()
+}
+
+/*
+Creates an aggregator for each aggregation along with the index of the column it
+aggregates in the schema, -1 when counting all rows.
+*/
+func newAggregators(schema *arrow.Schema, aggregations []Aggregation) ([]aggregator, []int, error) {
+	aggregators := make([]aggregator, 0, len(aggregations))
+	columnIdxs := make([]int, 0, len(aggregations))
+	for _, aggregation := range aggregations {
+		agg, columnIdx, err := newAggregator(schema, aggregation)
+		if err != nil {
+			for _, agg := range aggregators {
+				agg.release()
+			}
+			return nil, nil, errs.Wrap(err, fmt.Errorf("failed to create aggregator %s", aggregation.Name()))
+		}
+		aggregators = append(aggregators, agg)
+		columnIdxs = append(columnIdxs, columnIdx)
+	}
+	return aggregators, columnIdxs, nil
+}
+
+func newAggregator(schema *arrow.Schema, aggregation Aggregation) (aggregator, int, error) {
+	if aggregation.function == countAggregate && aggregation.column == CountAllColumns {
+		return &countAggregator{countAll: true}, -1, nil
+	}
+
+	columnIdxs := schema.FieldIndices(aggregation.column)
+	if len(columnIdxs) == 0 {
+		return nil, 0, errs.NewStackError(fmt.Errorf("%w| column name: %s", ErrColumnNotFound, aggregation.column))
+	}
+	field := schema.Field(columnIdxs[0])
+
+	switch aggregation.function {
+	case sumAggregate:
+		kind, ok := numericKindOf(field.Type)
+		if !ok {
+			return nil, 0, errs.NewStackError(fmt.Errorf("%w| can not sum %s", ErrUnsupportedDataType, field.Type))
+		}
+		return &sumAggregator{kind: kind}, columnIdxs[0], nil
+	case countAggregate:
+		return &countAggregator{}, columnIdxs[0], nil
+	case minAggregate, maxAggregate:
+		compare, err := newArrayValueComparator(field.Type)
+		if err != nil {
+			return nil, 0, err
+		}
+		return &extremeAggregator{
+			max:      aggregation.function == maxAggregate,
+			dt:       field.Type,
+			compare:  compare,
+			sources:  make([]arrow.Array, 0),
+			bestRows: make([]uint32, 0),
+		}, columnIdxs[0], nil
+	case meanAggregate:
+		if _, ok := numericKindOf(field.Type); !ok {
+			return nil, 0, errs.NewStackError(fmt.Errorf("%w| can not compute the mean of %s", ErrUnsupportedDataType, field.Type))
+		}
+		return &meanAggregator{}, columnIdxs[0], nil
+	default:
+		encoder, err := NewRowEncoder(arrow.NewSchema([]arrow.Field{field}, nil), SortKeysFromColumns(field.Name))
+		if err != nil {
+			return nil, 0, err
+		}
+		return &countDistinctAggregator{encoder: encoder}, columnIdxs[0], nil
+	}
+}
+
+func numericKindOf(dataType arrow.DataType) (numericKind, bool) {
+	switch dataType.ID() {
+	case arrow.INT8, arrow.INT16, arrow.INT32, arrow.INT64:
+		return signedNumeric, true
+	case arrow.UINT8, arrow.UINT16, arrow.UINT32, arrow.UINT64:
+		return unsignedNumeric, true
+	case arrow.FLOAT16, arrow.FLOAT32, arrow.FLOAT64:
+		return floatNumeric, true
+	default:
+		return 0, false
+	}
+}
+
+/*
+Returns the state of the groups after the first numGroups groups.
+*/
+func shiftGroups[T any](values []T, numGroups int) []T {
+	n := copy(values, values[numGroups:])
+	clear(values[n:])
+	return values[:n]
+}
+
+func growGroups[T any](values []T, numGroups int, zero T) []T {
+	for len(values) < numGroups {
+		values = append(values, zero)
+	}
+	return values
+}
+
+type countAggregator struct {
+	countAll bool
+	counts   []int64
+}
+
+func (a *countAggregator) dataType() arrow.DataType {
+	return arrow.PrimitiveTypes.Int64
+}
+
+func (a *countAggregator) resize(numGroups int) {
+	a.counts = growGroups(a.counts, numGroups, 0)
+}
+
+func (a *countAggregator) update(arr arrow.Array, groupIDs []uint32) {
+	for i, group := range groupIDs {
+		if a.countAll || arr.IsValid(i) {
+			a.counts[group]++
+		}
+	}
+}
+
+func (a *countAggregator) flush(mem *memory.GoAllocator, numGroups int) (arrow.Array, error) {
+	b := array.NewInt64Builder(mem)
+	defer b.Release()
+	b.AppendValues(a.counts[:numGroups], nil)
+	a.counts = shiftGroups(a.counts, numGroups)
+	return b.NewArray(), nil
+}
+
+func (a *countAggregator) release() {}
+
+type sumAggregator struct {
+	kind   numericKind
+	ints   []int64
+	uints  []uint64
+	floats []float64
+	valid  []bool
+}
+
+func (a *sumAggregator) dataType() arrow.DataType {
+	switch a.kind {
+	case signedNumeric:
+		return arrow.PrimitiveTypes.Int64
+	case unsignedNumeric:
+		return arrow.PrimitiveTypes.Uint64
+	default:
+		return arrow.PrimitiveTypes.Float64
+	}
+}
+
+func (a *sumAggregator) resize(numGroups int) {
+	switch a.kind {
+	case signedNumeric:
+		a.ints = growGroups(a.ints, numGroups, 0)
+	case unsignedNumeric:
+		a.uints = growGroups(a.uints, numGroups, 0)
+	default:
+		a.floats = growGroups(a.floats, numGroups, 0)
+	}
+	a.valid = growGroups(a.valid, numGroups, false)
+}
+
+func (a *sumAggregator) update(arr arrow.Array, groupIDs []uint32) {
+	for i, group := range groupIDs {
+		if arr.IsNull(i) {
+			continue
+		}
+		value, _ := numericArrayValue(arr, i)
+		switch a.kind {
+		case signedNumeric:
+			a.ints[group] += value.i
+		case unsignedNumeric:
+			a.uints[group] += value.u
+		default:
+			a.floats[group] += value.f
+		}
+		a.valid[group] = true
+	}
+}
+
+func (a *sumAggregator) flush(mem *memory.GoAllocator, numGroups int) (arrow.Array, error) {
+	valid := a.valid[:numGroups]
+	var result arrow.Array
+	switch a.kind {
+	case signedNumeric:
+		b := array.NewInt64Builder(mem)
+		defer b.Release()
+		b.AppendValues(a.ints[:numGroups], valid)
+		result = b.NewArray()
+		a.ints = shiftGroups(a.ints, numGroups)
+	case unsignedNumeric:
+		b := array.NewUint64Builder(mem)
+		defer b.Release()
+		b.AppendValues(a.uints[:numGroups], valid)
+		result = b.NewArray()
+		a.uints = shiftGroups(a.uints, numGroups)
+	default:
+		b := array.NewFloat64Builder(mem)
+		defer b.Release()
+		b.AppendValues(a.floats[:numGroups], valid)
+		result = b.NewArray()
+		a.floats = shiftGroups(a.floats, numGroups)
+	}
+	a.valid = shiftGroups(a.valid, numGroups)
+	return result, nil
+}
+
+func (a *sumAggregator) release() {}
+
+type meanAggregator struct {
+	sums   []float64
+	counts []int64
+}
+
+func (a *meanAggregator) dataType() arrow.DataType {
+	return arrow.PrimitiveTypes.Float64
+}
+
+func (a *meanAggregator) resize(numGroups int) {
+	a.sums = growGroups(a.sums, numGroups, 0)
+	a.counts = growGroups(a.counts, numGroups, 0)
+}
+
+func (a *meanAggregator) update(arr arrow.Array, groupIDs []uint32) {
+	for i, group := range groupIDs {
+		if arr.IsNull(i) {
+			continue
+		}
+		value, _ := numericArrayValue(arr, i)
+		a.sums[group] += value.float64()
+		a.counts[group]++
+	}
+}
+
+func (a *meanAggregator) flush(mem *memory.GoAllocator, numGroups int) (arrow.Array, error) {
+	b := array.NewFloat64Builder(mem)
+	defer b.Release()
+	b.Reserve(numGroups)
+	for group := 0; group < numGroups; group++ {
+		if a.counts[group] == 0 {
+			b.AppendNull()
+			continue
+		}
+		b.Append(a.sums[group] / float64(a.counts[group]))
+	}
+	a.sums = shiftGroups(a.sums, numGroups)
+	a.counts = shiftGroups(a.counts, numGroups)
+	return b.NewArray(), nil
+}
+
+func (a *meanAggregator) release() {}
+
+/*
+Tracks the minimum or maximum value of each group as a row in one of the
+arrays it has seen, so values can be of any type the comparator supports.
+*/
+type extremeAggregator struct {
+	max     bool
+	dt      arrow.DataType
+	compare arrayValueComparator
+	sources []arrow.Array
+	// the index of the source with the best value, -1 when there is no value
+	bestSources []int
+	bestRows    []uint32
+}
+
+func (a *extremeAggregator) dataType() arrow.DataType {
+	return a.dt
+}
+
+func (a *extremeAggregator) resize(numGroups int) {
+	a.bestSources = growGroups(a.bestSources, numGroups, -1)
+	a.bestRows = growGroups(a.bestRows, numGroups, 0)
+}
+
+func (a *extremeAggregator) update(arr arrow.Array, groupIDs []uint32) {
+	if len(a.sources) == 0 || a.sources[len(a.sources)-1] != arr {
+		arr.Retain()
+		a.sources = append(a.sources, arr)
+	}
+	sourceIdx := len(a.sources) - 1
+
+	for i, group := range groupIDs {
+		if arr.IsNull(i) {
+			continue
+		}
+		if a.bestSources[group] != -1 {
+			compareValue := a.compare(arr, a.sources[a.bestSources[group]], i, int(a.bestRows[group]))
+			if (a.max && compareValue <= 0) || (!a.max && compareValue >= 0) {
+				continue
+			}
+		}
+		a.bestSources[group] = sourceIdx
+		a.bestRows[group] = uint32(i)
+	}
+}
+
+func (a *extremeAggregator) flush(mem *memory.GoAllocator, numGroups int) (arrow.Array, error) {
+	if len(a.sources) == 0 {
+		return array.MakeArrayOfNull(mem, a.dt, numGroups), nil
+	}
+
+	sourcesBuilder := array.NewUint32Builder(mem)
+	defer sourcesBuilder.Release()
+	rowsBuilder := array.NewUint32Builder(mem)
+	defer rowsBuilder.Release()
+	for group := 0; group < numGroups; group++ {
+		if a.bestSources[group] == -1 {
+			sourcesBuilder.AppendNull()
+			rowsBuilder.AppendNull()
+			continue
+		}
+		sourcesBuilder.Append(uint32(a.bestSources[group]))
+		rowsBuilder.Append(a.bestRows[group])
+	}
+	sourceIndices := sourcesBuilder.NewArray()
+	defer sourceIndices.Release()
+	rowIndices := rowsBuilder.NewArray()
+	defer rowIndices.Release()
+	indices := array.NewRecord(
+		arrow.NewSchema([]arrow.Field{
+			{Name: "source", Type: arrow.PrimitiveTypes.Uint32, Nullable: true},
+			{Name: "row", Type: arrow.PrimitiveTypes.Uint32, Nullable: true},
+		}, nil),
+		[]arrow.Array{sourceIndices, rowIndices},
+		int64(numGroups),
+	)
+	defer indices.Release()
+
+	result, err := TakeMultipleArrays(mem, a.sources, indices)
+	if err != nil {
+		return nil, err
+	}
+
+	a.bestSources = shiftGroups(a.bestSources, numGroups)
+	a.bestRows = shiftGroups(a.bestRows, numGroups)
+	a.releaseUnusedSources()
+	return result, nil
+}
+
+/*
+Releases the sources that no group refers to, keeping the most recent
+source since it's likely to be updated again.
+*/
+func (a *extremeAggregator) releaseUnusedSources() {
+	used := make([]bool, len(a.sources))
+	used[len(used)-1] = true
+	for _, sourceIdx := range a.bestSources {
+		if sourceIdx != -1 {
+			used[sourceIdx] = true
+		}
+	}
+
+	newIdxs := make([]int, len(a.sources))
+	sources := a.sources[:0]
+	for idx, source := range a.sources {
+		if !used[idx] {
+			source.Release()
+			continue
+		}
+		newIdxs[idx] = len(sources)
+		sources = append(sources, source)
+	}
+	clear(a.sources[len(sources):])
+	a.sources = sources
+	for group, sourceIdx := range a.bestSources {
+		if sourceIdx != -1 {
+			a.bestSources[group] = newIdxs[sourceIdx]
+		}
+	}
+}
+
+func (a *extremeAggregator) release() {
+	for _, source := range a.sources {
+		source.Release()
+	}
+	a.sources = nil
+}
+
+/*
+Counts the distinct values of each group by storing the encoded values.
+*/
+type countDistinctAggregator struct {
+	encoder *RowEncoder
+	values  []map[string]struct{}
+}
+
+func (a *countDistinctAggregator) dataType() arrow.DataType {
+	return arrow.PrimitiveTypes.Int64
+}
+
+func (a *countDistinctAggregator) resize(numGroups int) {
+	a.values = growGroups(a.values, numGroups, nil)
+}
+
+func (a *countDistinctAggregator) update(arr arrow.Array, groupIDs []uint32) {
+	record := array.NewRecord(a.encoder.Schema(), []arrow.Array{arr}, int64(arr.Len()))
+	defer record.Release()
+
+	var key []byte
+	for i, group := range groupIDs {
+		if arr.IsNull(i) {
+			continue
+		}
+		if a.values[group] == nil {
+			a.values[group] = make(map[string]struct{})
+		}
+		key = a.encoder.AppendRow(key[:0], record, i)
+		a.values[group][string(key)] = struct{}{}
+	}
+}
+
+func (a *countDistinctAggregator) flush(mem *memory.GoAllocator, numGroups int) (arrow.Array, error) {
+	b := array.NewInt64Builder(mem)
+	defer b.Release()
+	b.Reserve(numGroups)
+	for group := 0; group < numGroups; group++ {
+		b.Append(int64(len(a.values[group])))
+	}
+	a.values = shiftGroups(a.values, numGroups)
+	return b.NewArray(), nil
+}
+
+func (a *countDistinctAggregator) release() {}
+
+/*
+Builds the record returned by an aggregation from the group keys and the
+result of each aggregator. The schema only depends on the aggregations so
+records from consecutive batches can be concatenated.
+*/
+func aggregateOutput(keys arrow.Record, aggregations []Aggregation, results []arrow.Array) arrow.Record {
+	fields := make([]arrow.Field, 0, int(keys.NumCols())+len(results))
+	fields = append(fields, keys.Schema().Fields()...)
+	columns := make([]arrow.Array, 0, int(keys.NumCols())+len(results))
+	columns = append(columns, keys.Columns()...)
+	for idx, result := range results {
+		fields = append(fields, arrow.Field{
+			Name:     aggregations[idx].Name(),
+			Type:     result.DataType(),
+			Nullable: aggregations[idx].function != countAggregate && aggregations[idx].function != countDistinctAggregate,
+		})
+		columns = append(columns, result)
+	}
+	return array.NewRecord(arrow.NewSchema(fields, nil), columns, keys.NumRows())
+}
+
+func releaseArrays(arrays []arrow.Array) {
+	for _, arr := range arrays {
+		arr.Release()
+	}
+}
@@ -0,0 +1,29 @@
+package arrowops
+
+import (
+	"fmt"
+
+	"github.com/alekLukanen/errs"
+
+	"github.com/apache/arrow/go/v17/arrow"
+	"github.com/apache/arrow/go/v17/arrow/array"
+)
+
+/*
+Returns a new record with the column appended as a nullable field with the name
+provided, for example the result of a kernel computed from the record's columns.
+The existing columns are not copied, but referenced from the original record.
+*/
+func AppendColumn(rec arrow.Record, name string, column arrow.Array) (arrow.Record, error) {
+	if int64(column.Len()) != rec.NumRows() {
+		return nil, errs.NewStackError(fmt.Errorf("%w| column has %d values but the record has %d rows", ErrLengthsNotEqual, column.Len(), rec.NumRows()))
+	}
+
+	fields := append(make([]arrow.Field, 0, rec.NumCols()+1), rec.Schema().Fields()...)
+	fields = append(fields, arrow.Field{Name: name, Type: column.DataType(), Nullable: true})
+	columns := append(make([]arrow.Array, 0, rec.NumCols()+1), rec.Columns()...)
+	columns = append(columns, column)
+
+	metadata := rec.Schema().Metadata()
+	return array.NewRecord(arrow.NewSchema(fields, &metadata), columns, rec.NumRows()), nil
+}
@@ -0,0 +1,619 @@
+package arrowops
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+
+	"github.com/alekLukanen/errs"
+	"github.com/apache/arrow/go/v17/arrow"
+	"github.com/apache/arrow/go/v17/arrow/array"
+	"github.com/apache/arrow/go/v17/arrow/decimal128"
+	"github.com/apache/arrow/go/v17/arrow/decimal256"
+	"github.com/apache/arrow/go/v17/arrow/memory"
+)
+
+type ArithmeticOperator int
+
+const (
+	AddOperator ArithmeticOperator = iota
+	SubtractOperator
+	MultiplyOperator
+	DivideOperator
+	ModuloOperator
+)
+
+func (op ArithmeticOperator) String() string {
+	switch op {
+	case AddOperator:
+		return "+"
+	case SubtractOperator:
+		return "-"
+	case MultiplyOperator:
+		return "*"
+	case DivideOperator:
+		return "/"
+	default:
+		return "%"
+	}
+}
+
+/*
+Controls how overflow is handled. By default integer results wrap around like
+Go arithmetic and decimal results are not checked against the precision of the
+result type. When CheckOverflow is set ErrOverflow is returned instead, and
+float division by zero returns ErrDivideByZero instead of an infinity or NaN.
+Integer and decimal division by zero always returns ErrDivideByZero.
+*/
+type ArithmeticOptions struct {
+	CheckOverflow bool
+}
+
+/*
+Applies the operator to each pair of values in the arrays, which must have the
+same length. The result is null where either value is null.
+
+Arrays with the same type produce that type. Otherwise numeric types are
+promoted to the wider type of the same kind, or to INT64 for signed and
+unsigned integers and FLOAT64 for integers and floats. Decimals can be
+combined with decimals and integers, the result is a decimal with a precision
+and scale that can hold the result, capped at the maximum precision: the
+larger scale for addition, subtraction and modulo, the sum of the scales for
+multiplication and at least 4 digits for division. Division of integers and
+decimals truncates towards zero.
+*/
+func ArithmeticArrays(mem *memory.GoAllocator, op ArithmeticOperator, left, right arrow.Array, opts ArithmeticOptions) (arrow.Array, error) {
+	if left.Len() != right.Len() {
+		return nil, errs.NewStackError(fmt.Errorf("%w| left has %d values and right has %d values", ErrLengthsNotEqual, left.Len(), right.Len()))
+	}
+	return arithmetic(mem, op, exprValue{arr: left}, exprValue{arr: right}, left.Len(), opts)
+}
+
+/*
+Applies the operator to each value in the array and the scalar value, which can
+be any value supported by ExprLiteral.
+*/
+func ArithmeticArrayScalar(mem *memory.GoAllocator, op ArithmeticOperator, left arrow.Array, right any, opts ArithmeticOptions) (arrow.Array, error) {
+	// literals do not read the record they are evaluated against
+	rightValue, err := ExprLiteral(right).evaluate(mem, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer rightValue.release()
+	return arithmetic(mem, op, exprValue{arr: left}, rightValue, left.Len(), opts)
+}
+
+/*
+Same as ArithmeticArrayScalar with the scalar value on the left of the operator.
+*/
+func ArithmeticScalarArray(mem *memory.GoAllocator, op ArithmeticOperator, left any, right arrow.Array, opts ArithmeticOptions) (arrow.Array, error) {
+	leftValue, err := ExprLiteral(left).evaluate(mem, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer leftValue.release()
+	return arithmetic(mem, op, leftValue, exprValue{arr: right}, right.Len(), opts)
+}
+
+/*
+Negates each value of a numeric or decimal array. Negating an unsigned integer
+wraps around unless the value is zero.
+*/
+func Negate(mem *memory.GoAllocator, arr arrow.Array, opts ArithmeticOptions) (arrow.Array, error) {
+	return unaryArithmetic(mem, arr, negateOperator, 0, opts)
+}
+
+/*
+The absolute value of each value of a numeric or decimal array.
+*/
+func Abs(mem *memory.GoAllocator, arr arrow.Array, opts ArithmeticOptions) (arrow.Array, error) {
+	return unaryArithmetic(mem, arr, absOperator, 0, opts)
+}
+
+/*
+Rounds each value of a numeric or decimal array to the number of digits after
+the decimal point, or before it when digits is negative, with halves rounded
+away from zero. The result has the same type as the array so integers are only
+changed by a negative number of digits.
+*/
+func Round(mem *memory.GoAllocator, arr arrow.Array, digits int, opts ArithmeticOptions) (arrow.Array, error) {
+	return unaryArithmetic(mem, arr, roundOperator, digits, opts)
+}
+
+func arithmetic(mem *memory.GoAllocator, op ArithmeticOperator, left, right exprValue, length int, opts ArithmeticOptions) (arrow.Array, error) {
+	resultType, err := arithmeticResultType(op, left.arr.DataType(), right.arr.DataType())
+	if err != nil {
+		return nil, err
+	}
+
+	b := array.NewBuilder(mem, resultType)
+	defer b.Release()
+	b.Reserve(length)
+
+	if resultType.ID() == arrow.NULL {
+		// both operands are null so every value of the result is null
+		b.AppendNulls(length)
+		return b.NewArray(), nil
+	}
+
+	var apply func(row int) error
+	if decimalType, ok := resultType.(arrow.DecimalType); ok {
+		apply = decimalArithmetic(b, op, left, right, decimalType, opts)
+	} else {
+		apply = numericArithmetic(b, op, left, right, opts)
+	}
+	for row := 0; row < length; row++ {
+		if left.isNull(row) || right.isNull(row) {
+			b.AppendNull()
+			continue
+		}
+		if err := apply(row); err != nil {
+			return nil, errs.Wrap(err, fmt.Errorf("failed to compute row %d", row))
+		}
+	}
+	return b.NewArray(), nil
+}
+
+func arithmeticResultType(op ArithmeticOperator, left, right arrow.DataType) (arrow.DataType, error) {
+	// a null operand, like ExprLiteral(nil), has the type of the other operand
+	switch {
+	case left.ID() == arrow.NULL && right.ID() == arrow.NULL:
+		return arrow.Null, nil
+	case left.ID() == arrow.NULL:
+		left = right
+	case right.ID() == arrow.NULL:
+		right = left
+	}
+
+	_, leftDecimal := left.(arrow.DecimalType)
+	_, rightDecimal := right.(arrow.DecimalType)
+	if leftDecimal || rightDecimal {
+		return decimalResultType(op, left, right)
+	}
+
+	leftKind, leftOk := numericKindOf(left)
+	rightKind, rightOk := numericKindOf(right)
+	if !leftOk || !rightOk {
+		return nil, errs.NewStackError(fmt.Errorf("%w| can not compute %s %s %s", ErrUnsupportedDataType, left, op, right))
+	}
+	if arrow.TypeEqual(left, right) {
+		return left, nil
+	}
+
+	switch {
+	case leftKind == rightKind:
+		if left.(arrow.FixedWidthDataType).BitWidth() >= right.(arrow.FixedWidthDataType).BitWidth() {
+			return left, nil
+		}
+		return right, nil
+	case leftKind == floatNumeric || rightKind == floatNumeric:
+		return arrow.PrimitiveTypes.Float64, nil
+	default:
+		return arrow.PrimitiveTypes.Int64, nil
+	}
+}
+
+func decimalResultType(op ArithmeticOperator, left, right arrow.DataType) (arrow.DataType, error) {
+	leftPrecision, leftScale, leftOk := decimalPrecisionScale(left)
+	rightPrecision, rightScale, rightOk := decimalPrecisionScale(right)
+	if !leftOk || !rightOk {
+		return nil, errs.NewStackError(fmt.Errorf("%w| can not compute %s %s %s", ErrUnsupportedDataType, left, op, right))
+	}
+
+	var precision, scale int32
+	switch op {
+	case AddOperator, SubtractOperator:
+		scale = max(leftScale, rightScale)
+		precision = max(leftPrecision-leftScale, rightPrecision-rightScale) + scale + 1
+	case MultiplyOperator:
+		scale = leftScale + rightScale
+		precision = leftPrecision + rightPrecision + 1
+	case DivideOperator:
+		scale = max(4, leftScale+rightPrecision-rightScale+1)
+		precision = leftPrecision - leftScale + rightScale + scale
+	default:
+		scale = max(leftScale, rightScale)
+		precision = min(leftPrecision-leftScale, rightPrecision-rightScale) + scale
+	}
+
+	id, maxPrecision := arrow.DECIMAL128, int32(decimal128.MaxPrecision)
+	if left.ID() == arrow.DECIMAL256 || right.ID() == arrow.DECIMAL256 {
+		id, maxPrecision = arrow.DECIMAL256, decimal256.MaxPrecision
+	}
+	precision = min(max(precision, 1), maxPrecision)
+	scale = min(scale, precision)
+	return arrow.NewDecimalType(id, precision, scale)
+}
+
+/*
+The precision and scale of a decimal type, or the number of
+digits needed for any value of an integer type.
+*/
+func decimalPrecisionScale(dataType arrow.DataType) (int32, int32, bool) {
+	switch dt := dataType.(type) {
+	case arrow.DecimalType:
+		return dt.GetPrecision(), dt.GetScale(), true
+	}
+	switch dataType.ID() {
+	case arrow.INT8, arrow.UINT8:
+		return 3, 0, true
+	case arrow.INT16, arrow.UINT16:
+		return 5, 0, true
+	case arrow.INT32, arrow.UINT32:
+		return 10, 0, true
+	case arrow.INT64:
+		return 19, 0, true
+	case arrow.UINT64:
+		return 20, 0, true
+	default:
+		return 0, 0, false
+	}
+}
+
+func decimalArrayValue(arr arrow.Array, i int) decimal256.Num {
+	switch a := arr.(type) {
+	case *array.Decimal128:
+		return decimal256.FromDecimal128(a.Value(i))
+	case *array.Decimal256:
+		return a.Value(i)
+	default:
+		value, _ := numericArrayValue(arr, i)
+		if value.kind == unsignedNumeric {
+			return decimal256.FromU64(value.u)
+		}
+		return decimal256.FromI64(value.i)
+	}
+}
+
+/*
+Changes the scale of the value, truncating digits when the scale is reduced.
+*/
+func rescaleDecimal(n decimal256.Num, scale, targetScale int32) (decimal256.Num, error) {
+	switch {
+	case targetScale == scale:
+		return n, nil
+	case targetScale < scale:
+		if scale-targetScale > decimal256.MaxPrecision {
+			return decimal256.Num{}, nil
+		}
+		return n.ReduceScaleBy(scale-targetScale, false), nil
+	case targetScale-scale > decimal256.MaxPrecision:
+		return decimal256.Num{}, errs.NewStackError(fmt.Errorf("%w| can not increase the scale of a decimal by %d", ErrOverflow, targetScale-scale))
+	default:
+		return n.IncreaseScaleBy(targetScale - scale), nil
+	}
+}
+
+func decimalArithmetic(b array.Builder, op ArithmeticOperator, left, right exprValue, resultType arrow.DecimalType, opts ArithmeticOptions) func(row int) error {
+	scale := resultType.GetScale()
+	_, leftScale, _ := decimalPrecisionScale(left.arr.DataType())
+	_, rightScale, _ := decimalPrecisionScale(right.arr.DataType())
+
+	return func(row int) error {
+		l := decimalArrayValue(left.arr, left.index(row))
+		r := decimalArrayValue(right.arr, right.index(row))
+		if (op == DivideOperator || op == ModuloOperator) && r.Sign() == 0 {
+			return errs.NewStackError(fmt.Errorf("%w| %s %s %s", ErrDivideByZero, l.ToString(leftScale), op, r.ToString(rightScale)))
+		}
+
+		var result decimal256.Num
+		var err error
+		switch op {
+		case MultiplyOperator:
+			result, err = rescaleDecimal(l.Mul(r), leftScale+rightScale, scale)
+		case DivideOperator:
+			// scale the dividend so the quotient has the result scale
+			if l, err = rescaleDecimal(l, leftScale, scale+rightScale); err == nil {
+				result, _ = l.Div(r)
+			}
+		default:
+			if l, err = rescaleDecimal(l, leftScale, scale); err != nil {
+				return err
+			}
+			if r, err = rescaleDecimal(r, rightScale, scale); err != nil {
+				return err
+			}
+			switch op {
+			case AddOperator:
+				result = l.Add(r)
+			case SubtractOperator:
+				result = l.Sub(r)
+			default:
+				_, result = l.Div(r)
+			}
+		}
+		if err != nil {
+			return err
+		}
+		return appendDecimal(b, result, resultType, opts)
+	}
+}
+
+func appendDecimal(b array.Builder, n decimal256.Num, dataType arrow.DecimalType, opts ArithmeticOptions) error {
+	if opts.CheckOverflow && !n.FitsInPrecision(dataType.GetPrecision()) {
+		return errs.NewStackError(fmt.Errorf("%w| %s does not fit in %s", ErrOverflow, n.ToString(dataType.GetScale()), dataType))
+	}
+	switch b := b.(type) {
+	case *array.Decimal128Builder:
+		words := n.Array()
+		b.Append(decimal128.New(int64(words[1]), words[0]))
+	case *array.Decimal256Builder:
+		b.Append(n)
+	}
+	return nil
+}
+
+func numericArithmetic(b array.Builder, op ArithmeticOperator, left, right exprValue, opts ArithmeticOptions) func(row int) error {
+	kind, _ := numericKindOf(b.Type())
+	bitWidth := b.Type().(arrow.FixedWidthDataType).BitWidth()
+
+	return func(row int) error {
+		l, _ := numericArrayValue(left.arr, left.index(row))
+		r, _ := numericArrayValue(right.arr, right.index(row))
+		l, err := promoteNumeric(l, kind, opts)
+		if err != nil {
+			return err
+		}
+		r, err = promoteNumeric(r, kind, opts)
+		if err != nil {
+			return err
+		}
+		result, err := applyNumericOperator(op, l, r, bitWidth, opts)
+		if err != nil {
+			return err
+		}
+		// the result has been checked for overflow so it can be narrowed to the result type
+		return appendCastNumeric(b, result, CastOptions{AllowLossy: true})
+	}
+}
+
+/*
+Converts the value to the kind of the result. Unsigned values larger than
+the maximum INT64 wrap around when promoted to a signed value, or return
+ErrOverflow when CheckOverflow is set.
+*/
+func promoteNumeric(v numericValue, kind numericKind, opts ArithmeticOptions) (numericValue, error) {
+	switch {
+	case v.kind == kind:
+		return v, nil
+	case kind == floatNumeric:
+		return numericValue{kind: floatNumeric, f: v.float64()}, nil
+	default:
+		if opts.CheckOverflow && v.u > math.MaxInt64 {
+			return numericValue{}, errs.NewStackError(fmt.Errorf("%w| %s does not fit in a 64 bit signed integer", ErrOverflow, v))
+		}
+		return numericValue{kind: signedNumeric, i: int64(v.u)}, nil
+	}
+}
+
+func applyNumericOperator(op ArithmeticOperator, l, r numericValue, bitWidth int, opts ArithmeticOptions) (numericValue, error) {
+	isDivision := op == DivideOperator || op == ModuloOperator
+	if isDivision && ((l.kind == signedNumeric && r.i == 0) || (l.kind == unsignedNumeric && r.u == 0) || (l.kind == floatNumeric && r.f == 0 && opts.CheckOverflow)) {
+		return numericValue{}, errs.NewStackError(fmt.Errorf("%w| %s %s %s", ErrDivideByZero, l, op, r))
+	}
+
+	result := numericValue{kind: l.kind}
+	overflow := false
+	switch l.kind {
+	case floatNumeric:
+		switch op {
+		case AddOperator:
+			result.f = l.f + r.f
+		case SubtractOperator:
+			result.f = l.f - r.f
+		case MultiplyOperator:
+			result.f = l.f * r.f
+		case DivideOperator:
+			result.f = l.f / r.f
+		default:
+			result.f = math.Mod(l.f, r.f)
+		}
+		return result, nil
+	case signedNumeric:
+		a, b := l.i, r.i
+		switch op {
+		case AddOperator:
+			result.i = a + b
+			overflow = (a >= 0) == (b >= 0) && (result.i >= 0) != (a >= 0)
+		case SubtractOperator:
+			result.i = a - b
+			overflow = (a >= 0) != (b >= 0) && (result.i >= 0) != (a >= 0)
+		case MultiplyOperator:
+			result.i = a * b
+			overflow = a != 0 && (result.i/a != b || (a == -1 && b == math.MinInt64))
+		case DivideOperator:
+			result.i = a / b
+			overflow = a == math.MinInt64 && b == -1
+		default:
+			result.i = a % b
+		}
+		overflow = overflow || !signedFits(result.i, bitWidth)
+	default:
+		a, b := l.u, r.u
+		switch op {
+		case AddOperator:
+			result.u = a + b
+			overflow = result.u < a
+		case SubtractOperator:
+			result.u = a - b
+			overflow = b > a
+		case MultiplyOperator:
+			var hi uint64
+			hi, result.u = bits.Mul64(a, b)
+			overflow = hi != 0
+		case DivideOperator:
+			result.u = a / b
+		default:
+			result.u = a % b
+		}
+		overflow = overflow || !unsignedFits(result.u, bitWidth)
+	}
+
+	if overflow && opts.CheckOverflow {
+		return numericValue{}, errs.NewStackError(fmt.Errorf("%w| %s %s %s overflows a %d bit integer", ErrOverflow, l, op, r, bitWidth))
+	}
+	return result, nil
+}
+
+func signedFits(value int64, bitWidth int) bool {
+	minValue := int64(-1) << (bitWidth - 1)
+	return value >= minValue && value <= -(minValue+1)
+}
+
+func unsignedFits(value uint64, bitWidth int) bool {
+	return value <= uint64(1)<<bitWidth-1
+}
+
+type unaryOperator int
+
+const (
+	negateOperator unaryOperator = iota
+	absOperator
+	roundOperator
+)
+
+func (op unaryOperator) String() string {
+	switch op {
+	case negateOperator:
+		return "negate"
+	case absOperator:
+		return "abs"
+	default:
+		return "round"
+	}
+}
+
+func unaryArithmetic(mem *memory.GoAllocator, arr arrow.Array, op unaryOperator, digits int, opts ArithmeticOptions) (arrow.Array, error) {
+	decimalType, isDecimal := arr.DataType().(arrow.DecimalType)
+	if _, ok := numericKindOf(arr.DataType()); !ok && !isDecimal {
+		return nil, errs.NewStackError(fmt.Errorf("%w| can not compute %s of %s", ErrUnsupportedDataType, op, arr.DataType()))
+	}
+
+	b := array.NewBuilder(mem, arr.DataType())
+	defer b.Release()
+	b.Reserve(arr.Len())
+	for row := 0; row < arr.Len(); row++ {
+		if arr.IsNull(row) {
+			b.AppendNull()
+			continue
+		}
+
+		var err error
+		if isDecimal {
+			result := applyDecimalUnary(op, decimalArrayValue(arr, row), decimalType.GetScale(), digits)
+			err = appendDecimal(b, result, decimalType, opts)
+		} else {
+			value, _ := numericArrayValue(arr, row)
+			var result numericValue
+			result, err = applyNumericUnary(op, value, arr.DataType().(arrow.FixedWidthDataType).BitWidth(), digits, opts)
+			if err == nil {
+				err = appendCastNumeric(b, result, CastOptions{AllowLossy: true})
+			}
+		}
+		if err != nil {
+			return nil, errs.Wrap(err, fmt.Errorf("failed to compute %s of row %d", op, row))
+		}
+	}
+	return b.NewArray(), nil
+}
+
+func applyDecimalUnary(op unaryOperator, n decimal256.Num, scale int32, digits int) decimal256.Num {
+	switch op {
+	case negateOperator:
+		return n.Negate()
+	case absOperator:
+		return n.Abs()
+	default:
+		return roundDecimal(n, scale-int32(digits))
+	}
+}
+
+/*
+Rounds away the last digits of the value, with halves rounded away from zero,
+keeping the scale of the value.
+*/
+func roundDecimal(n decimal256.Num, digits int32) decimal256.Num {
+	switch {
+	case digits <= 0:
+		return n
+	case digits > decimal256.MaxPrecision:
+		// every value is less than half of the rounding unit
+		return decimal256.Num{}
+	default:
+		return n.ReduceScaleBy(digits, true).IncreaseScaleBy(digits)
+	}
+}
+
+/*
+Rounds the value to the number of digits after the decimal point, with
+halves rounded away from zero.
+*/
+func roundFloatDigits(f float64, digits int) float64 {
+	scale := math.Pow10(digits)
+	switch {
+	case math.IsInf(f, 0) || math.IsNaN(f):
+		return f
+	case scale == 0:
+		// the rounding unit is larger than any float so every value rounds to zero
+		return math.Copysign(0, f)
+	case math.IsInf(scale, 0):
+		// the rounding unit is smaller than the precision of any float
+		return f
+	}
+	scaled := f * scale
+	if math.IsInf(scaled, 0) || math.Abs(scaled) >= 1<<53 {
+		// the float has no digits smaller than the rounding unit
+		return f
+	}
+	return math.Round(scaled) / scale
+}
+
+func applyNumericUnary(op unaryOperator, v numericValue, bitWidth int, digits int, opts ArithmeticOptions) (numericValue, error) {
+	result := numericValue{kind: v.kind}
+	overflow := false
+	switch v.kind {
+	case floatNumeric:
+		switch op {
+		case negateOperator:
+			result.f = -v.f
+		case absOperator:
+			result.f = math.Abs(v.f)
+		default:
+			result.f = roundFloatDigits(v.f, digits)
+		}
+		return result, nil
+	case signedNumeric:
+		switch op {
+		case negateOperator:
+			result.i = -v.i
+			overflow = v.i == math.MinInt64 || !signedFits(result.i, bitWidth)
+		case absOperator:
+			result.i = v.i
+			if v.i < 0 {
+				result.i = -v.i
+			}
+			overflow = v.i == math.MinInt64 || !signedFits(result.i, bitWidth)
+		default:
+			rounded := roundDecimal(decimal256.FromI64(v.i), int32(-digits)).BigInt()
+			result.i = rounded.Int64()
+			overflow = !rounded.IsInt64() || !signedFits(result.i, bitWidth)
+		}
+	default:
+		switch op {
+		case negateOperator:
+			result.u = -v.u
+			overflow = v.u != 0
+		case absOperator:
+			result.u = v.u
+		default:
+			rounded := roundDecimal(decimal256.FromU64(v.u), int32(-digits)).BigInt()
+			result.u = rounded.Uint64()
+			overflow = !rounded.IsUint64() || !unsignedFits(result.u, bitWidth)
+		}
+	}
+
+	if overflow && opts.CheckOverflow {
+		return numericValue{}, errs.NewStackError(fmt.Errorf("%w| %s of %s overflows a %d bit integer", ErrOverflow, op, v, bitWidth))
+	}
+	return result, nil
+}
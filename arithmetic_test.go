@@ -0,0 +1,431 @@
+package arrowops
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/apache/arrow/go/v17/arrow"
+	"github.com/apache/arrow/go/v17/arrow/array"
+	"github.com/apache/arrow/go/v17/arrow/memory"
+)
+
+func TestArithmeticArrays(t *testing.T) {
+
+	mem := memory.NewGoAllocator()
+
+	decimal52 := &arrow.Decimal128Type{Precision: 5, Scale: 2}
+	decimal51 := &arrow.Decimal128Type{Precision: 5, Scale: 1}
+
+	testCases := []struct {
+		caseName       string
+		op             ArithmeticOperator
+		leftType       arrow.DataType
+		left           string
+		rightType      arrow.DataType
+		right          string
+		checkOverflow  bool
+		expectedType   arrow.DataType
+		expectedValues []string
+		expectedErr    error
+	}{
+		{
+			caseName: "add_with_nulls", op: AddOperator,
+			leftType: arrow.PrimitiveTypes.Int32, left: "[1, null, 3]",
+			rightType: arrow.PrimitiveTypes.Int32, right: "[10, 20, null]",
+			expectedType: arrow.PrimitiveTypes.Int32, expectedValues: []string{"11", "(null)", "(null)"},
+		},
+		{
+			caseName: "add_wraps", op: AddOperator,
+			leftType: arrow.PrimitiveTypes.Int8, left: "[127]",
+			rightType: arrow.PrimitiveTypes.Int8, right: "[1]",
+			expectedType: arrow.PrimitiveTypes.Int8, expectedValues: []string{"-128"},
+		},
+		{
+			caseName: "add_checked", op: AddOperator,
+			leftType: arrow.PrimitiveTypes.Int8, left: "[127]",
+			rightType: arrow.PrimitiveTypes.Int8, right: "[1]",
+			checkOverflow: true, expectedErr: ErrOverflow,
+		},
+		{
+			caseName: "multiply_checked", op: MultiplyOperator,
+			leftType: arrow.PrimitiveTypes.Int64, left: "[4611686018427387904]",
+			rightType: arrow.PrimitiveTypes.Int64, right: "[2]",
+			checkOverflow: true, expectedErr: ErrOverflow,
+		},
+		{
+			caseName: "subtract_unsigned_wraps", op: SubtractOperator,
+			leftType: arrow.PrimitiveTypes.Uint8, left: "[1, 5]",
+			rightType: arrow.PrimitiveTypes.Uint8, right: "[2, 3]",
+			expectedType: arrow.PrimitiveTypes.Uint8, expectedValues: []string{"255", "2"},
+		},
+		{
+			caseName: "subtract_unsigned_checked", op: SubtractOperator,
+			leftType: arrow.PrimitiveTypes.Uint8, left: "[1]",
+			rightType: arrow.PrimitiveTypes.Uint8, right: "[2]",
+			checkOverflow: true, expectedErr: ErrOverflow,
+		},
+		{
+			caseName: "divide_promotes_to_wider_type", op: DivideOperator,
+			leftType: arrow.PrimitiveTypes.Int32, left: "[7, -7]",
+			rightType: arrow.PrimitiveTypes.Int64, right: "[2, 2]",
+			expectedType: arrow.PrimitiveTypes.Int64, expectedValues: []string{"3", "-3"},
+		},
+		{
+			caseName: "modulo", op: ModuloOperator,
+			leftType: arrow.PrimitiveTypes.Int32, left: "[7, -7]",
+			rightType: arrow.PrimitiveTypes.Int32, right: "[2, 2]",
+			expectedType: arrow.PrimitiveTypes.Int32, expectedValues: []string{"1", "-1"},
+		},
+		{
+			caseName: "integer_divide_by_zero", op: DivideOperator,
+			leftType: arrow.PrimitiveTypes.Int32, left: "[1]",
+			rightType: arrow.PrimitiveTypes.Int32, right: "[0]",
+			expectedErr: ErrDivideByZero,
+		},
+		{
+			caseName: "float_divide_by_zero", op: DivideOperator,
+			leftType: arrow.PrimitiveTypes.Float64, left: "[1]",
+			rightType: arrow.PrimitiveTypes.Float64, right: "[0]",
+			expectedType: arrow.PrimitiveTypes.Float64, expectedValues: []string{"+Inf"},
+		},
+		{
+			caseName: "float_divide_by_zero_checked", op: DivideOperator,
+			leftType: arrow.PrimitiveTypes.Float64, left: "[1]",
+			rightType: arrow.PrimitiveTypes.Float64, right: "[0]",
+			checkOverflow: true, expectedErr: ErrDivideByZero,
+		},
+		{
+			caseName: "integer_and_float", op: AddOperator,
+			leftType: arrow.PrimitiveTypes.Int32, left: "[1]",
+			rightType: arrow.PrimitiveTypes.Float32, right: "[0.5]",
+			expectedType: arrow.PrimitiveTypes.Float64, expectedValues: []string{"1.5"},
+		},
+		{
+			caseName: "signed_and_unsigned", op: SubtractOperator,
+			leftType: arrow.PrimitiveTypes.Uint32, left: "[1]",
+			rightType: arrow.PrimitiveTypes.Int8, right: "[3]",
+			expectedType: arrow.PrimitiveTypes.Int64, expectedValues: []string{"-2"},
+		},
+		{
+			caseName: "signed_and_unsigned_checked", op: AddOperator,
+			leftType: arrow.PrimitiveTypes.Uint64, left: "[9223372036854775808]",
+			rightType: arrow.PrimitiveTypes.Int64, right: "[1]",
+			checkOverflow: true, expectedErr: ErrOverflow,
+		},
+		{
+			caseName: "decimal_add", op: AddOperator,
+			leftType: decimal52, left: `["1.25", "-3.00"]`,
+			rightType: decimal51, right: `["2.5", "1.1"]`,
+			expectedType:   &arrow.Decimal128Type{Precision: 7, Scale: 2},
+			expectedValues: []string{"3.75", "-1.9"},
+		},
+		{
+			caseName: "decimal_multiply", op: MultiplyOperator,
+			leftType: decimal52, left: `["1.25"]`,
+			rightType: decimal51, right: `["2.5"]`,
+			expectedType:   &arrow.Decimal128Type{Precision: 11, Scale: 3},
+			expectedValues: []string{"3.125"},
+		},
+		{
+			caseName: "decimal_divide", op: DivideOperator,
+			leftType: decimal52, left: `["1.00"]`,
+			rightType: decimal51, right: `["3.0"]`,
+			expectedType:   &arrow.Decimal128Type{Precision: 11, Scale: 7},
+			expectedValues: []string{"0.3333333"},
+		},
+		{
+			caseName: "decimal_modulo", op: ModuloOperator,
+			leftType: decimal52, left: `["7.25"]`,
+			rightType: decimal51, right: `["2.0"]`,
+			expectedType:   &arrow.Decimal128Type{Precision: 5, Scale: 2},
+			expectedValues: []string{"1.25"},
+		},
+		{
+			caseName: "decimal_and_integer", op: AddOperator,
+			leftType: decimal52, left: `["1.25"]`,
+			rightType: arrow.PrimitiveTypes.Int32, right: "[2]",
+			expectedType:   &arrow.Decimal128Type{Precision: 13, Scale: 2},
+			expectedValues: []string{"3.25"},
+		},
+		{
+			caseName: "decimal_divide_by_zero", op: DivideOperator,
+			leftType: decimal52, left: `["1.25"]`,
+			rightType: decimal51, right: `["0.0"]`,
+			expectedErr: ErrDivideByZero,
+		},
+		{
+			caseName: "unsupported_type", op: AddOperator,
+			leftType: arrow.BinaryTypes.String, left: `["a"]`,
+			rightType: arrow.PrimitiveTypes.Int32, right: "[1]",
+			expectedErr: ErrUnsupportedDataType,
+		},
+		{
+			caseName: "null_operands", op: AddOperator,
+			leftType: arrow.Null, left: "[null, null]",
+			rightType: arrow.Null, right: "[null, null]",
+			expectedType: arrow.Null, expectedValues: []string{"(null)", "(null)"},
+		},
+		{
+			caseName: "null_and_integer", op: MultiplyOperator,
+			leftType: arrow.Null, left: "[null, null]",
+			rightType: arrow.PrimitiveTypes.Int16, right: "[1, null]",
+			expectedType: arrow.PrimitiveTypes.Int16, expectedValues: []string{"(null)", "(null)"},
+		},
+		{
+			caseName: "lengths_not_equal", op: AddOperator,
+			leftType: arrow.PrimitiveTypes.Int32, left: "[1, 2]",
+			rightType: arrow.PrimitiveTypes.Int32, right: "[1]",
+			expectedErr: ErrLengthsNotEqual,
+		},
+	}
+
+	for idx, tc := range testCases {
+		t.Run(fmt.Sprintf("case_%d:%s", idx, tc.caseName), func(t *testing.T) {
+			left := castTestArray(t, mem, tc.leftType, tc.left)
+			defer left.Release()
+			right := castTestArray(t, mem, tc.rightType, tc.right)
+			defer right.Release()
+
+			result, err := ArithmeticArrays(mem, tc.op, left, right, ArithmeticOptions{CheckOverflow: tc.checkOverflow})
+			if tc.expectedErr != nil {
+				if !errors.Is(err, tc.expectedErr) {
+					t.Errorf("expected error %v, got %v", tc.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("received unexpected error: %s", err)
+			}
+			defer result.Release()
+
+			if !arrow.TypeEqual(result.DataType(), tc.expectedType) {
+				t.Errorf("expected type %s, got %s", tc.expectedType, result.DataType())
+			}
+			if actual := arrayValueStrings(result); !slices.Equal(actual, tc.expectedValues) {
+				t.Errorf("expected values %v, got %v", tc.expectedValues, actual)
+			}
+		})
+	}
+
+}
+
+func TestArithmeticScalar(t *testing.T) {
+	mem := memory.NewGoAllocator()
+
+	arr := castTestArray(t, mem, arrow.PrimitiveTypes.Int32, "[1, 2, null]")
+	defer arr.Release()
+
+	testCases := []struct {
+		caseName       string
+		compute        func() (arrow.Array, error)
+		expectedType   arrow.DataType
+		expectedValues []string
+	}{
+		{
+			caseName: "array_scalar",
+			compute: func() (arrow.Array, error) {
+				return ArithmeticArrayScalar(mem, MultiplyOperator, arr, int32(10), ArithmeticOptions{})
+			},
+			expectedType:   arrow.PrimitiveTypes.Int32,
+			expectedValues: []string{"10", "20", "(null)"},
+		},
+		{
+			caseName: "scalar_array",
+			compute: func() (arrow.Array, error) {
+				return ArithmeticScalarArray(mem, SubtractOperator, 10, arr, ArithmeticOptions{})
+			},
+			expectedType:   arrow.PrimitiveTypes.Int64,
+			expectedValues: []string{"9", "8", "(null)"},
+		},
+		{
+			caseName: "null_scalar",
+			compute: func() (arrow.Array, error) {
+				return ArithmeticArrayScalar(mem, AddOperator, arr, nil, ArithmeticOptions{})
+			},
+			expectedType:   arrow.PrimitiveTypes.Int32,
+			expectedValues: []string{"(null)", "(null)", "(null)"},
+		},
+	}
+
+	for idx, tc := range testCases {
+		t.Run(fmt.Sprintf("case_%d:%s", idx, tc.caseName), func(t *testing.T) {
+			result, err := tc.compute()
+			if err != nil {
+				t.Fatalf("received unexpected error: %s", err)
+			}
+			defer result.Release()
+
+			if !arrow.TypeEqual(result.DataType(), tc.expectedType) {
+				t.Errorf("expected type %s, got %s", tc.expectedType, result.DataType())
+			}
+			if actual := arrayValueStrings(result); !slices.Equal(actual, tc.expectedValues) {
+				t.Errorf("expected values %v, got %v", tc.expectedValues, actual)
+			}
+		})
+	}
+}
+
+func TestUnaryArithmetic(t *testing.T) {
+	mem := memory.NewGoAllocator()
+
+	negate := func(arr arrow.Array, opts ArithmeticOptions) (arrow.Array, error) {
+		return Negate(mem, arr, opts)
+	}
+	abs := func(arr arrow.Array, opts ArithmeticOptions) (arrow.Array, error) {
+		return Abs(mem, arr, opts)
+	}
+	round := func(digits int) func(arr arrow.Array, opts ArithmeticOptions) (arrow.Array, error) {
+		return func(arr arrow.Array, opts ArithmeticOptions) (arrow.Array, error) {
+			return Round(mem, arr, digits, opts)
+		}
+	}
+
+	testCases := []struct {
+		caseName       string
+		compute        func(arr arrow.Array, opts ArithmeticOptions) (arrow.Array, error)
+		dataType       arrow.DataType
+		values         string
+		checkOverflow  bool
+		expectedValues []string
+		expectedErr    error
+	}{
+		{
+			caseName: "negate", compute: negate, dataType: arrow.PrimitiveTypes.Int32,
+			values: "[1, null, -3]", expectedValues: []string{"-1", "(null)", "3"},
+		},
+		{
+			caseName: "negate_wraps", compute: negate, dataType: arrow.PrimitiveTypes.Int8,
+			values: "[-128]", expectedValues: []string{"-128"},
+		},
+		{
+			caseName: "negate_checked", compute: negate, dataType: arrow.PrimitiveTypes.Int8,
+			values: "[-128]", checkOverflow: true, expectedErr: ErrOverflow,
+		},
+		{
+			caseName: "negate_decimal", compute: negate, dataType: &arrow.Decimal128Type{Precision: 5, Scale: 2},
+			values: `["1.25"]`, expectedValues: []string{"-1.25"},
+		},
+		{
+			caseName: "abs", compute: abs, dataType: arrow.PrimitiveTypes.Float64,
+			values: "[-1.5, 2]", expectedValues: []string{"1.5", "2"},
+		},
+		{
+			caseName: "round_float", compute: round(1), dataType: arrow.PrimitiveTypes.Float64,
+			values: "[1.25, -1.55, 123.456]", expectedValues: []string{"1.3", "-1.6", "123.5"},
+		},
+		{
+			caseName: "round_float_to_large_unit", compute: round(-400), dataType: arrow.PrimitiveTypes.Float64,
+			values: "[123.456, -1e308]", expectedValues: []string{"0", "-0"},
+		},
+		{
+			caseName: "round_float_to_small_unit", compute: round(400), dataType: arrow.PrimitiveTypes.Float64,
+			values: "[123.456, 0, 1e300]", expectedValues: []string{"123.456", "0", "1e+300"},
+		},
+		{
+			caseName: "round_float_beyond_precision", compute: round(20), dataType: arrow.PrimitiveTypes.Float64,
+			values: "[0.1, 1e10]", expectedValues: []string{"0.1", "1e+10"},
+		},
+		{
+			caseName: "round_integer", compute: round(-2), dataType: arrow.PrimitiveTypes.Int32,
+			values: "[1234, -1250, 49]", expectedValues: []string{"1200", "-1300", "0"},
+		},
+		{
+			caseName: "round_integer_checked", compute: round(-1), dataType: arrow.PrimitiveTypes.Int8,
+			values: "[127]", checkOverflow: true, expectedErr: ErrOverflow,
+		},
+		{
+			caseName: "round_decimal", compute: round(1), dataType: &arrow.Decimal128Type{Precision: 6, Scale: 3},
+			values: `["1.250", "-1.249"]`, expectedValues: []string{"1.3", "-1.2"},
+		},
+		{
+			caseName: "round_decimal_checked", compute: round(0), dataType: &arrow.Decimal128Type{Precision: 3, Scale: 1},
+			values: `["99.9"]`, checkOverflow: true, expectedErr: ErrOverflow,
+		},
+		{
+			caseName: "unsupported_type", compute: abs, dataType: arrow.BinaryTypes.String,
+			values: `["a"]`, expectedErr: ErrUnsupportedDataType,
+		},
+	}
+
+	for idx, tc := range testCases {
+		t.Run(fmt.Sprintf("case_%d:%s", idx, tc.caseName), func(t *testing.T) {
+			arr := castTestArray(t, mem, tc.dataType, tc.values)
+			defer arr.Release()
+
+			result, err := tc.compute(arr, ArithmeticOptions{CheckOverflow: tc.checkOverflow})
+			if tc.expectedErr != nil {
+				if !errors.Is(err, tc.expectedErr) {
+					t.Errorf("expected error %v, got %v", tc.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("received unexpected error: %s", err)
+			}
+			defer result.Release()
+
+			if !arrow.TypeEqual(result.DataType(), tc.dataType) {
+				t.Errorf("expected type %s, got %s", tc.dataType, result.DataType())
+			}
+			if actual := arrayValueStrings(result); !slices.Equal(actual, tc.expectedValues) {
+				t.Errorf("expected values %v, got %v", tc.expectedValues, actual)
+			}
+		})
+	}
+}
+
+func TestAppendColumn(t *testing.T) {
+	mem := memory.NewGoAllocator()
+
+	record := recordFromJSON(t, mem, arrow.NewSchema(
+		[]arrow.Field{
+			{Name: "dept", Type: arrow.BinaryTypes.String},
+			{Name: "salary", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+			{Name: "id", Type: arrow.PrimitiveTypes.Int32},
+		}, nil), `[
+			{"dept": "a", "salary": 10, "id": 0},
+			{"dept": "b", "salary": 20, "id": 1},
+			{"dept": "a", "salary": 30, "id": 2},
+			{"dept": "a", "salary": 10, "id": 3},
+			{"dept": "b", "salary": null, "id": 4},
+			{"dept": "a", "salary": 40, "id": 5}
+		]`,
+	)
+	defer record.Release()
+
+	doubled, err := ArithmeticArrayScalar(mem, MultiplyOperator, record.Column(1), int64(2), ArithmeticOptions{CheckOverflow: true})
+	if err != nil {
+		t.Fatalf("received unexpected error: %s", err)
+	}
+	defer doubled.Release()
+
+	result, err := AppendColumn(record, "doubled", doubled)
+	if err != nil {
+		t.Fatalf("received unexpected error: %s", err)
+	}
+	defer result.Release()
+
+	expectedRows := []string{
+		"a,10,0,20",
+		"b,20,1,40",
+		"a,30,2,60",
+		"a,10,3,20",
+		"b,(null),4,(null)",
+		"a,40,5,80",
+	}
+	if actual := recordRowStrings(result); !slices.Equal(actual, expectedRows) {
+		t.Errorf("expected rows %v, got %v", expectedRows, actual)
+	}
+	if result.ColumnName(3) != "doubled" || !result.Schema().Field(3).Nullable {
+		t.Errorf("expected a nullable doubled column, got %s", result.Schema())
+	}
+
+	short := array.NewSlice(doubled, 0, 2)
+	defer short.Release()
+	_, err = AppendColumn(record, "short", short)
+	if !errors.Is(err, ErrLengthsNotEqual) {
+		t.Errorf("expected error %v, got %v", ErrLengthsNotEqual, err)
+	}
+}
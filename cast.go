@@ -0,0 +1,484 @@
+package arrowops
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/alekLukanen/errs"
+	"github.com/apache/arrow/go/v17/arrow"
+	"github.com/apache/arrow/go/v17/arrow/array"
+	"github.com/apache/arrow/go/v17/arrow/float16"
+	"github.com/apache/arrow/go/v17/arrow/memory"
+)
+
+const nanosPerDay = int64(24 * time.Hour)
+
+/*
+Controls how values that can not be represented exactly in the target type
+are cast. By default casts are safe and return ErrLossyCast for these values,
+including floats that lose precision when narrowed, like 0.1 cast from FLOAT64
+to FLOAT32. Strings are parsed to the nearest value of a float type. When
+AllowLossy is set numbers are rounded, truncated or wrap around like a Go
+conversion, temporal values are truncated to the target unit and strings
+that can not be parsed become null.
+*/
+type CastOptions struct {
+	AllowLossy bool
+}
+
+/*
+Converts the values of the array to the target type. The supported casts are
+between numeric types, between strings and numeric, boolean or temporal types,
+between timestamps, Date32 and Date64, and between durations. Timestamps are
+instants in UTC, so changing the timezone of a timestamp does not change its
+values and the date of a timestamp is its date in UTC. Dictionary arrays are
+decoded and their values are then cast to the target type. Null values stay
+null.
+*/
+func CastArray(mem *memory.GoAllocator, arr arrow.Array, targetType arrow.DataType, opts CastOptions) (arrow.Array, error) {
+	if arrow.TypeEqual(arr.DataType(), targetType) {
+		arr.Retain()
+		return arr, nil
+	}
+
+	if dict, ok := arr.(*array.Dictionary); ok {
+		decoded, err := decodeDictionary(mem, dict)
+		if err != nil {
+			return nil, err
+		}
+		defer decoded.Release()
+		return CastArray(mem, decoded, targetType, opts)
+	}
+
+	if source, ok := arr.DataType().(*arrow.TimestampType); ok {
+		if target, ok := targetType.(*arrow.TimestampType); ok && source.Unit == target.Unit {
+			// only the timezone is different so the values can be reused
+			data := array.NewData(targetType, arr.Len(), arr.Data().Buffers(), nil, arr.NullN(), arr.Data().Offset())
+			defer data.Release()
+			return array.MakeFromData(data), nil
+		}
+	}
+
+	castValue, ok := newValueCaster(arr.DataType(), targetType)
+	if !ok {
+		return nil, errs.NewStackError(fmt.Errorf("%w| can not cast %s to %s", ErrUnsupportedDataType, arr.DataType(), targetType))
+	}
+
+	b := array.NewBuilder(mem, targetType)
+	defer b.Release()
+	b.Reserve(arr.Len())
+	for i := 0; i < arr.Len(); i++ {
+		if arr.IsNull(i) {
+			b.AppendNull()
+			continue
+		}
+		if err := castValue(b, arr, i, opts); err != nil {
+			return nil, errs.Wrap(err, fmt.Errorf("failed to cast row %d from %s to %s", i, arr.DataType(), targetType))
+		}
+	}
+	return b.NewArray(), nil
+}
+
+/*
+Casts each column of the record to the type of the field with the same name in
+the target schema using safe casts. The returned record has the target schema.
+*/
+func CastRecord(mem *memory.GoAllocator, record arrow.Record, targetSchema *arrow.Schema) (arrow.Record, error) {
+	return CastRecordWithOptions(mem, record, targetSchema, CastOptions{})
+}
+
+/*
+Same as CastRecord but with options controlling lossy casts. Returns
+ErrColumnNotFound when a target field is not in the record and
+ErrNullValuesNotAllowed when a column with nulls is cast to a field
+that is not nullable.
+*/
+func CastRecordWithOptions(mem *memory.GoAllocator, record arrow.Record, targetSchema *arrow.Schema, opts CastOptions) (arrow.Record, error) {
+	record.Retain()
+	defer record.Release()
+
+	columns := make([]arrow.Array, 0, targetSchema.NumFields())
+	defer func() {
+		releaseArrays(columns)
+	}()
+	for _, field := range targetSchema.Fields() {
+		columnIdxs := record.Schema().FieldIndices(field.Name)
+		if len(columnIdxs) == 0 {
+			return nil, errs.NewStackError(fmt.Errorf("%w| column name: %s", ErrColumnNotFound, field.Name))
+		}
+		column, err := CastArray(mem, record.Column(columnIdxs[0]), field.Type, opts)
+		if err != nil {
+			return nil, errs.Wrap(err, fmt.Errorf("failed to cast column %s", field.Name))
+		}
+		columns = append(columns, column)
+		if !field.Nullable && column.NullN() > 0 {
+			return nil, errs.NewStackError(fmt.Errorf("%w| column %s has %d null values", ErrNullValuesNotAllowed, field.Name, column.NullN()))
+		}
+	}
+
+	return array.NewRecord(targetSchema, columns, record.NumRows()), nil
+}
+
+/*
+Appends the value at row i of the array, which is not null, to the builder
+of the target type.
+*/
+type valueCaster func(b array.Builder, arr arrow.Array, i int, opts CastOptions) error
+
+func newValueCaster(sourceType, targetType arrow.DataType) (valueCaster, bool) {
+	_, sourceNumeric := numericKindOf(sourceType)
+	targetKind, targetNumeric := numericKindOf(targetType)
+	sourceString := isStringType(sourceType)
+
+	switch {
+	case targetNumeric && sourceNumeric:
+		return func(b array.Builder, arr arrow.Array, i int, opts CastOptions) error {
+			value, _ := numericArrayValue(arr, i)
+			return appendCastNumeric(b, value, opts)
+		}, true
+	case targetNumeric && sourceString:
+		return func(b array.Builder, arr arrow.Array, i int, opts CastOptions) error {
+			s, _ := stringArrayValue(arr, i)
+			value, err := parseNumericValue(s, targetKind)
+			if err != nil {
+				return castParseError(b, s, targetType, err, opts)
+			}
+			if targetKind == floatNumeric {
+				// the text is rounded to the nearest value of the target type like
+				// strconv.ParseFloat does, values that overflow are still lossy
+				if rounded := roundFloat(value.f, targetType.(arrow.FixedWidthDataType).BitWidth()); !math.IsInf(rounded, 0) {
+					value.f = rounded
+				}
+			}
+			return appendCastNumeric(b, value, opts)
+		}, true
+	case isStringType(targetType) && (sourceNumeric || sourceString || sourceType.ID() == arrow.BOOL || isInstantType(sourceType)):
+		return func(b array.Builder, arr arrow.Array, i int, opts CastOptions) error {
+			appendString(b, arr.ValueStr(i))
+			return nil
+		}, true
+	case targetType.ID() == arrow.BOOL && sourceString:
+		return func(b array.Builder, arr arrow.Array, i int, opts CastOptions) error {
+			s, _ := stringArrayValue(arr, i)
+			value, err := strconv.ParseBool(s)
+			if err != nil {
+				return castParseError(b, s, targetType, err, opts)
+			}
+			b.(*array.BooleanBuilder).Append(value)
+			return nil
+		}, true
+	case isInstantType(targetType) && isInstantType(sourceType):
+		sourceNanos := instantNanosPerUnit(sourceType)
+		return func(b array.Builder, arr arrow.Array, i int, opts CastOptions) error {
+			return appendCastInstant(b, instantArrayValue(arr, i), sourceNanos, opts)
+		}, true
+	case targetType.ID() == arrow.DURATION && sourceType.ID() == arrow.DURATION:
+		sourceNanos := int64(sourceType.(*arrow.DurationType).Unit.Multiplier())
+		targetNanos := int64(targetType.(*arrow.DurationType).Unit.Multiplier())
+		return func(b array.Builder, arr arrow.Array, i int, opts CastOptions) error {
+			value, err := convertInstant(int64(arr.(*array.Duration).Value(i)), sourceNanos, targetNanos, opts)
+			if err != nil {
+				return err
+			}
+			b.(*array.DurationBuilder).Append(arrow.Duration(value))
+			return nil
+		}, true
+	case isInstantType(targetType) && sourceString:
+		// dates are parsed in seconds and then checked for a time of day
+		unit := arrow.Second
+		if timestampType, ok := targetType.(*arrow.TimestampType); ok {
+			unit = timestampType.Unit
+		}
+		return func(b array.Builder, arr arrow.Array, i int, opts CastOptions) error {
+			s, _ := stringArrayValue(arr, i)
+			value, err := arrow.TimestampFromString(s, unit)
+			if err != nil {
+				return castParseError(b, s, targetType, err, opts)
+			}
+			return appendCastInstant(b, int64(value), int64(unit.Multiplier()), opts)
+		}, true
+	default:
+		return nil, false
+	}
+}
+
+func castParseError(b array.Builder, s string, targetType arrow.DataType, err error, opts CastOptions) error {
+	if opts.AllowLossy {
+		b.AppendNull()
+		return nil
+	}
+	return errs.NewStackError(fmt.Errorf("%w| can not parse %q as %s: %s", ErrLossyCast, s, targetType, err))
+}
+
+func isStringType(dataType arrow.DataType) bool {
+	return dataType.ID() == arrow.STRING || dataType.ID() == arrow.LARGE_STRING
+}
+
+/*
+Types storing a point in time as a number of units since the epoch.
+*/
+func isInstantType(dataType arrow.DataType) bool {
+	switch dataType.ID() {
+	case arrow.TIMESTAMP, arrow.DATE32, arrow.DATE64:
+		return true
+	default:
+		return false
+	}
+}
+
+func instantNanosPerUnit(dataType arrow.DataType) int64 {
+	switch dt := dataType.(type) {
+	case *arrow.TimestampType:
+		return int64(dt.Unit.Multiplier())
+	case *arrow.Date32Type:
+		return nanosPerDay
+	default:
+		return int64(time.Millisecond)
+	}
+}
+
+func instantArrayValue(arr arrow.Array, i int) int64 {
+	switch a := arr.(type) {
+	case *array.Timestamp:
+		return int64(a.Value(i))
+	case *array.Date32:
+		return int64(a.Value(i))
+	default:
+		return int64(a.(*array.Date64).Value(i))
+	}
+}
+
+func appendCastInstant(b array.Builder, value, sourceNanos int64, opts CastOptions) error {
+	switch b := b.(type) {
+	case *array.TimestampBuilder:
+		unit := b.Type().(*arrow.TimestampType).Unit
+		result, err := convertInstant(value, sourceNanos, int64(unit.Multiplier()), opts)
+		if err != nil {
+			return err
+		}
+		b.Append(arrow.Timestamp(result))
+	case *array.Date32Builder:
+		days, err := convertInstant(value, sourceNanos, nanosPerDay, opts)
+		if err != nil {
+			return err
+		}
+		if (days < math.MinInt32 || days > math.MaxInt32) && !opts.AllowLossy {
+			return errs.NewStackError(fmt.Errorf("%w| %d days overflows a Date32", ErrLossyCast, days))
+		}
+		b.Append(arrow.Date32(days))
+	case *array.Date64Builder:
+		// Date64 values are always a whole number of days
+		days, err := convertInstant(value, sourceNanos, nanosPerDay, opts)
+		if err != nil {
+			return err
+		}
+		result, err := convertInstant(days, nanosPerDay, int64(time.Millisecond), opts)
+		if err != nil {
+			return err
+		}
+		b.Append(arrow.Date64(result))
+	}
+	return nil
+}
+
+/*
+Converts a value from one unit to another, where the units are given as
+a number of nanoseconds. Values are truncated towards negative infinity
+when converted to a larger unit.
+*/
+func convertInstant(value, sourceNanos, targetNanos int64, opts CastOptions) (int64, error) {
+	switch {
+	case sourceNanos == targetNanos:
+		return value, nil
+	case sourceNanos > targetNanos:
+		factor := sourceNanos / targetNanos
+		if (value > math.MaxInt64/factor || value < math.MinInt64/factor) && !opts.AllowLossy {
+			return 0, errs.NewStackError(fmt.Errorf("%w| %d overflows when converted to a unit %d times smaller", ErrLossyCast, value, factor))
+		}
+		return value * factor, nil
+	default:
+		factor := targetNanos / sourceNanos
+		result := value / factor
+		if value%factor < 0 {
+			result--
+		}
+		if result*factor != value && !opts.AllowLossy {
+			return 0, errs.NewStackError(fmt.Errorf("%w| %d is truncated when converted to a unit %d times larger", ErrLossyCast, value, factor))
+		}
+		return result, nil
+	}
+}
+
+func parseNumericValue(s string, kind numericKind) (numericValue, error) {
+	switch kind {
+	case signedNumeric:
+		value, err := strconv.ParseInt(s, 10, 64)
+		return numericValue{kind: signedNumeric, i: value}, err
+	case unsignedNumeric:
+		value, err := strconv.ParseUint(s, 10, 64)
+		return numericValue{kind: unsignedNumeric, u: value}, err
+	default:
+		value, err := strconv.ParseFloat(s, 64)
+		return numericValue{kind: floatNumeric, f: value}, err
+	}
+}
+
+func appendCastNumeric(b array.Builder, value numericValue, opts CastOptions) error {
+	var err error
+	switch b := b.(type) {
+	case *array.Int8Builder:
+		var result int64
+		result, err = castSigned(value, 8, opts)
+		b.Append(int8(result))
+	case *array.Int16Builder:
+		var result int64
+		result, err = castSigned(value, 16, opts)
+		b.Append(int16(result))
+	case *array.Int32Builder:
+		var result int64
+		result, err = castSigned(value, 32, opts)
+		b.Append(int32(result))
+	case *array.Int64Builder:
+		var result int64
+		result, err = castSigned(value, 64, opts)
+		b.Append(result)
+	case *array.Uint8Builder:
+		var result uint64
+		result, err = castUnsigned(value, 8, opts)
+		b.Append(uint8(result))
+	case *array.Uint16Builder:
+		var result uint64
+		result, err = castUnsigned(value, 16, opts)
+		b.Append(uint16(result))
+	case *array.Uint32Builder:
+		var result uint64
+		result, err = castUnsigned(value, 32, opts)
+		b.Append(uint32(result))
+	case *array.Uint64Builder:
+		var result uint64
+		result, err = castUnsigned(value, 64, opts)
+		b.Append(result)
+	case *array.Float16Builder:
+		var result float64
+		result, err = castFloat(value, 16, opts)
+		b.Append(float16.New(float32(result)))
+	case *array.Float32Builder:
+		var result float64
+		result, err = castFloat(value, 32, opts)
+		b.Append(float32(result))
+	case *array.Float64Builder:
+		var result float64
+		result, err = castFloat(value, 64, opts)
+		b.Append(result)
+	}
+	return err
+}
+
+func castSigned(value numericValue, bits int, opts CastOptions) (int64, error) {
+	minValue := int64(-1) << (bits - 1)
+	maxValue := -(minValue + 1)
+
+	var result int64
+	var exact bool
+	switch value.kind {
+	case signedNumeric:
+		result = value.i
+		exact = value.i >= minValue && value.i <= maxValue
+	case unsignedNumeric:
+		result = int64(value.u)
+		exact = value.u <= uint64(maxValue)
+	default:
+		result = int64(value.f)
+		exact = value.f >= float64(minValue) && value.f < -float64(minValue) && value.f == math.Trunc(value.f)
+	}
+	if !exact && !opts.AllowLossy {
+		return 0, errs.NewStackError(fmt.Errorf("%w| %s can not be represented as a %d bit signed integer", ErrLossyCast, value, bits))
+	}
+	return result, nil
+}
+
+func castUnsigned(value numericValue, bits int, opts CastOptions) (uint64, error) {
+	maxValue := uint64(1)<<bits - 1
+
+	var result uint64
+	var exact bool
+	switch value.kind {
+	case signedNumeric:
+		result = uint64(value.i)
+		exact = value.i >= 0 && uint64(value.i) <= maxValue
+	case unsignedNumeric:
+		result = value.u
+		exact = value.u <= maxValue
+	default:
+		result = uint64(value.f)
+		exact = value.f >= 0 && value.f < float64(maxValue)+1 && value.f == math.Trunc(value.f)
+	}
+	if !exact && !opts.AllowLossy {
+		return 0, errs.NewStackError(fmt.Errorf("%w| %s can not be represented as a %d bit unsigned integer", ErrLossyCast, value, bits))
+	}
+	return result, nil
+}
+
+/*
+Integers must be represented exactly by the float and narrowed floats must
+keep the same value, NaN stays NaN.
+*/
+func castFloat(value numericValue, bits int, opts CastOptions) (float64, error) {
+	var result float64
+	var exact bool
+	switch value.kind {
+	case signedNumeric:
+		result = roundFloat(float64(value.i), bits)
+		exact = result >= -(1<<63) && result < 1<<63 && int64(result) == value.i
+	case unsignedNumeric:
+		result = roundFloat(float64(value.u), bits)
+		exact = result < 1<<64 && uint64(result) == value.u
+	default:
+		result = roundFloat(value.f, bits)
+		exact = result == value.f || (math.IsNaN(result) && math.IsNaN(value.f))
+	}
+	if !exact && !opts.AllowLossy {
+		return 0, errs.NewStackError(fmt.Errorf("%w| %s can not be represented as a %d bit float", ErrLossyCast, value, bits))
+	}
+	return result, nil
+}
+
+func roundFloat(f float64, bits int) float64 {
+	switch bits {
+	case 16:
+		return float64(float16.New(float32(f)).Float32())
+	case 32:
+		return float64(float32(f))
+	default:
+		return f
+	}
+}
+
+func decodeDictionary(mem *memory.GoAllocator, dict *array.Dictionary) (arrow.Array, error) {
+	indices := dict.Indices()
+	b := array.NewUint32Builder(mem)
+	defer b.Release()
+	b.Reserve(indices.Len())
+	for i := 0; i < indices.Len(); i++ {
+		if indices.IsNull(i) {
+			b.AppendNull()
+			continue
+		}
+		value, _ := numericArrayValue(indices, i)
+		index, err := castUnsigned(value, 32, CastOptions{})
+		if err != nil {
+			return nil, errs.Wrap(err, fmt.Errorf("invalid dictionary index at row %d", i))
+		}
+		b.Append(uint32(index))
+	}
+	indicesArray := b.NewUint32Array()
+	defer indicesArray.Release()
+
+	decoded, err := TakeArray(mem, dict.Dictionary(), indicesArray)
+	if err != nil {
+		return nil, errs.Wrap(err, fmt.Errorf("failed to decode dictionary"))
+	}
+	return decoded, nil
+}
@@ -7,7 +7,8 @@ import (
 
 /*
 Take in a slice of arrays and return the arrays in a new slice
-where each array is in it's base data type.
+where each array is in it's base data type. The values
+are not converted, use CastArray to convert them to another type.
 */
 func CastArraysToBaseDataType[T arrow.Array](arrays ...arrow.Array) ([]T, error) {
 	TArrays := make([]T, len(arrays))
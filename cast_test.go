@@ -0,0 +1,311 @@
+package arrowops
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/apache/arrow/go/v17/arrow"
+	"github.com/apache/arrow/go/v17/arrow/array"
+	"github.com/apache/arrow/go/v17/arrow/memory"
+)
+
+func castTestArray(t *testing.T, mem *memory.GoAllocator, dataType arrow.DataType, values string) arrow.Array {
+	arr, _, err := array.FromJSON(mem, dataType, strings.NewReader(values))
+	if err != nil {
+		t.Fatalf("received unexpected error: %s", err)
+	}
+	return arr
+}
+
+func arrayValueStrings(arr arrow.Array) []string {
+	values := make([]string, arr.Len())
+	for i := range values {
+		values[i] = arr.ValueStr(i)
+	}
+	return values
+}
+
+func TestCastArray(t *testing.T) {
+
+	mem := memory.NewGoAllocator()
+
+	timestampSeconds := &arrow.TimestampType{Unit: arrow.Second, TimeZone: "UTC"}
+	timestampMillis := &arrow.TimestampType{Unit: arrow.Millisecond, TimeZone: "UTC"}
+	newYorkSeconds := &arrow.TimestampType{Unit: arrow.Second, TimeZone: "America/New_York"}
+
+	testCases := []struct {
+		caseName       string
+		sourceType     arrow.DataType
+		values         string
+		targetType     arrow.DataType
+		allowLossy     bool
+		expectedValues []string
+		expectedErr    error
+	}{
+		{
+			caseName: "int64_to_int8", sourceType: arrow.PrimitiveTypes.Int64, values: "[1, null, -3]",
+			targetType: arrow.PrimitiveTypes.Int8, expectedValues: []string{"1", "(null)", "-3"},
+		},
+		{
+			caseName: "int64_to_int8_overflow", sourceType: arrow.PrimitiveTypes.Int64, values: "[300]",
+			targetType: arrow.PrimitiveTypes.Int8, expectedErr: ErrLossyCast,
+		},
+		{
+			caseName: "int64_to_int8_overflow_wraps", sourceType: arrow.PrimitiveTypes.Int64, values: "[300]",
+			targetType: arrow.PrimitiveTypes.Int8, allowLossy: true, expectedValues: []string{"44"},
+		},
+		{
+			caseName: "uint64_to_int64_overflow", sourceType: arrow.PrimitiveTypes.Uint64, values: "[18446744073709551615]",
+			targetType: arrow.PrimitiveTypes.Int64, expectedErr: ErrLossyCast,
+		},
+		{
+			caseName: "negative_int32_to_uint16", sourceType: arrow.PrimitiveTypes.Int32, values: "[-1]",
+			targetType: arrow.PrimitiveTypes.Uint16, expectedErr: ErrLossyCast,
+		},
+		{
+			caseName: "whole_float64_to_int32", sourceType: arrow.PrimitiveTypes.Float64, values: "[2, -7]",
+			targetType: arrow.PrimitiveTypes.Int32, expectedValues: []string{"2", "-7"},
+		},
+		{
+			caseName: "fractional_float64_to_int32", sourceType: arrow.PrimitiveTypes.Float64, values: "[1.5]",
+			targetType: arrow.PrimitiveTypes.Int32, expectedErr: ErrLossyCast,
+		},
+		{
+			caseName: "fractional_float64_to_int32_truncates", sourceType: arrow.PrimitiveTypes.Float64, values: "[1.5, -2.5]",
+			targetType: arrow.PrimitiveTypes.Int32, allowLossy: true, expectedValues: []string{"1", "-2"},
+		},
+		{
+			caseName: "exact_int32_to_float32", sourceType: arrow.PrimitiveTypes.Int32, values: "[16777216]",
+			targetType: arrow.PrimitiveTypes.Float32, expectedValues: []string{"1.6777216e+07"},
+		},
+		{
+			caseName: "inexact_int32_to_float32", sourceType: arrow.PrimitiveTypes.Int32, values: "[16777217]",
+			targetType: arrow.PrimitiveTypes.Float32, expectedErr: ErrLossyCast,
+		},
+		{
+			caseName: "float64_to_float32_overflow", sourceType: arrow.PrimitiveTypes.Float64, values: "[1e300]",
+			targetType: arrow.PrimitiveTypes.Float32, expectedErr: ErrLossyCast,
+		},
+		{
+			caseName: "exact_float64_to_float32", sourceType: arrow.PrimitiveTypes.Float64, values: "[0.5, -2]",
+			targetType: arrow.PrimitiveTypes.Float32, expectedValues: []string{"0.5", "-2"},
+		},
+		{
+			caseName: "inexact_float64_to_float32", sourceType: arrow.PrimitiveTypes.Float64, values: "[0.1]",
+			targetType: arrow.PrimitiveTypes.Float32, expectedErr: ErrLossyCast,
+		},
+		{
+			caseName: "inexact_float64_to_float32_rounds", sourceType: arrow.PrimitiveTypes.Float64, values: "[0.1]",
+			targetType: arrow.PrimitiveTypes.Float32, allowLossy: true, expectedValues: []string{"0.1"},
+		},
+		{
+			caseName: "string_to_float32", sourceType: arrow.BinaryTypes.String, values: `["0.1", null]`,
+			targetType: arrow.PrimitiveTypes.Float32, expectedValues: []string{"0.1", "(null)"},
+		},
+		{
+			caseName: "string_to_float32_overflow", sourceType: arrow.BinaryTypes.String, values: `["0.1", "1e300"]`,
+			targetType: arrow.PrimitiveTypes.Float32, expectedErr: ErrLossyCast,
+		},
+		{
+			caseName: "string_to_int32", sourceType: arrow.BinaryTypes.String, values: `["12", null, "-4"]`,
+			targetType: arrow.PrimitiveTypes.Int32, expectedValues: []string{"12", "(null)", "-4"},
+		},
+		{
+			caseName: "invalid_string_to_int32", sourceType: arrow.BinaryTypes.String, values: `["12", "x"]`,
+			targetType: arrow.PrimitiveTypes.Int32, expectedErr: ErrLossyCast,
+		},
+		{
+			caseName: "invalid_string_to_int32_is_null", sourceType: arrow.BinaryTypes.String, values: `["12", "x"]`,
+			targetType: arrow.PrimitiveTypes.Int32, allowLossy: true, expectedValues: []string{"12", "(null)"},
+		},
+		{
+			caseName: "string_to_float64", sourceType: arrow.BinaryTypes.String, values: `["1.25"]`,
+			targetType: arrow.PrimitiveTypes.Float64, expectedValues: []string{"1.25"},
+		},
+		{
+			caseName: "numbers_to_string", sourceType: arrow.PrimitiveTypes.Float64, values: "[1.5, null]",
+			targetType: arrow.BinaryTypes.LargeString, expectedValues: []string{"1.5", "(null)"},
+		},
+		{
+			caseName: "string_to_boolean", sourceType: arrow.BinaryTypes.String, values: `["true", "false"]`,
+			targetType: arrow.FixedWidthTypes.Boolean, expectedValues: []string{"true", "false"},
+		},
+		{
+			caseName: "timestamp_to_finer_unit", sourceType: timestampSeconds, values: "[1]",
+			targetType: timestampMillis, expectedValues: []string{"1970-01-01 00:00:01Z"},
+		},
+		{
+			caseName: "timestamp_to_coarser_unit", sourceType: timestampMillis, values: "[1500]",
+			targetType: timestampSeconds, expectedErr: ErrLossyCast,
+		},
+		{
+			caseName: "timestamp_to_coarser_unit_truncates", sourceType: timestampMillis, values: "[1500, -1500]",
+			targetType: timestampSeconds, allowLossy: true, expectedValues: []string{"1970-01-01 00:00:01Z", "1969-12-31 23:59:58Z"},
+		},
+		{
+			caseName: "timestamp_timezone", sourceType: timestampSeconds, values: "[1, null]",
+			targetType: newYorkSeconds, expectedValues: []string{"1969-12-31 19:00:01-0500", "(null)"},
+		},
+		{
+			caseName: "date32_to_timestamp", sourceType: arrow.FixedWidthTypes.Date32, values: `["1970-01-02"]`,
+			targetType: timestampSeconds, expectedValues: []string{"1970-01-02 00:00:00Z"},
+		},
+		{
+			caseName: "timestamp_to_date32", sourceType: timestampSeconds, values: "[86400]",
+			targetType: arrow.FixedWidthTypes.Date32, expectedValues: []string{"1970-01-02"},
+		},
+		{
+			caseName: "timestamp_with_time_to_date32", sourceType: timestampSeconds, values: "[86401]",
+			targetType: arrow.FixedWidthTypes.Date32, expectedErr: ErrLossyCast,
+		},
+		{
+			caseName: "timestamp_before_epoch_to_date64", sourceType: timestampSeconds, values: "[-1]",
+			targetType: arrow.FixedWidthTypes.Date64, allowLossy: true, expectedValues: []string{"1969-12-31"},
+		},
+		{
+			caseName: "date32_to_date64", sourceType: arrow.FixedWidthTypes.Date32, values: `["2024-03-01"]`,
+			targetType: arrow.FixedWidthTypes.Date64, expectedValues: []string{"2024-03-01"},
+		},
+		{
+			caseName: "string_to_date32", sourceType: arrow.BinaryTypes.String, values: `["2024-03-01"]`,
+			targetType: arrow.FixedWidthTypes.Date32, expectedValues: []string{"2024-03-01"},
+		},
+		{
+			caseName: "string_with_time_to_date32", sourceType: arrow.BinaryTypes.String, values: `["2024-03-01 10:00:00"]`,
+			targetType: arrow.FixedWidthTypes.Date32, expectedErr: ErrLossyCast,
+		},
+		{
+			caseName: "string_to_timestamp", sourceType: arrow.BinaryTypes.String, values: `["2024-03-01T10:00:00.5+01:00"]`,
+			targetType: timestampMillis, expectedValues: []string{"2024-03-01 09:00:00.5Z"},
+		},
+		{
+			caseName: "duration_to_coarser_unit", sourceType: arrow.FixedWidthTypes.Duration_ms, values: "[3000, 1500]",
+			targetType: arrow.FixedWidthTypes.Duration_s, expectedErr: ErrLossyCast,
+		},
+		{
+			caseName: "duration_to_finer_unit", sourceType: arrow.FixedWidthTypes.Duration_s, values: "[3, null]",
+			targetType: arrow.FixedWidthTypes.Duration_ms, expectedValues: []string{"3000ms", "(null)"},
+		},
+		{
+			caseName: "unsupported", sourceType: arrow.FixedWidthTypes.Boolean, values: "[true]",
+			targetType: timestampSeconds, expectedErr: ErrUnsupportedDataType,
+		},
+	}
+
+	for idx, tc := range testCases {
+		t.Run(fmt.Sprintf("case_%d:%s", idx, tc.caseName), func(t *testing.T) {
+			arr := castTestArray(t, mem, tc.sourceType, tc.values)
+			defer arr.Release()
+
+			result, err := CastArray(mem, arr, tc.targetType, CastOptions{AllowLossy: tc.allowLossy})
+			if tc.expectedErr != nil {
+				if !errors.Is(err, tc.expectedErr) {
+					t.Errorf("expected error %v, got %v", tc.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("received unexpected error: %s", err)
+			}
+			defer result.Release()
+
+			if !arrow.TypeEqual(result.DataType(), tc.targetType) {
+				t.Errorf("expected type %s, got %s", tc.targetType, result.DataType())
+			}
+			if actual := arrayValueStrings(result); !slices.Equal(actual, tc.expectedValues) {
+				t.Errorf("expected values %v, got %v", tc.expectedValues, actual)
+			}
+		})
+	}
+
+}
+
+func TestCastArrayDictionary(t *testing.T) {
+	mem := memory.NewGoAllocator()
+
+	b := array.NewDictionaryBuilder(mem, &arrow.DictionaryType{
+		IndexType: arrow.PrimitiveTypes.Int8,
+		ValueType: arrow.BinaryTypes.String,
+	}).(*array.BinaryDictionaryBuilder)
+	defer b.Release()
+	for _, value := range []string{"5", "", "7", "5"} {
+		if value == "" {
+			b.AppendNull()
+			continue
+		}
+		if err := b.AppendString(value); err != nil {
+			t.Fatalf("received unexpected error: %s", err)
+		}
+	}
+	dict := b.NewArray()
+	defer dict.Release()
+
+	result, err := CastArray(mem, dict, arrow.PrimitiveTypes.Int64, CastOptions{})
+	if err != nil {
+		t.Fatalf("received unexpected error: %s", err)
+	}
+	defer result.Release()
+
+	expectedValues := []string{"5", "(null)", "7", "5"}
+	if actual := arrayValueStrings(result); !slices.Equal(actual, expectedValues) {
+		t.Errorf("expected values %v, got %v", expectedValues, actual)
+	}
+}
+
+func TestCastRecord(t *testing.T) {
+	mem := memory.NewGoAllocator()
+
+	recBuilder := array.NewRecordBuilder(mem, arrow.NewSchema(
+		[]arrow.Field{
+			{Name: "id", Type: arrow.PrimitiveTypes.Int64},
+			{Name: "ts", Type: arrow.BinaryTypes.String, Nullable: true},
+			{Name: "extra", Type: arrow.BinaryTypes.String},
+		}, nil),
+	)
+	defer recBuilder.Release()
+	recBuilder.Field(0).(*array.Int64Builder).AppendValues([]int64{1, 2}, nil)
+	recBuilder.Field(1).(*array.StringBuilder).AppendValues([]string{"2024-03-01 10:00:00", ""}, []bool{true, false})
+	recBuilder.Field(2).(*array.StringBuilder).AppendValues([]string{"a", "b"}, nil)
+	record := recBuilder.NewRecord()
+	defer record.Release()
+
+	targetSchema := arrow.NewSchema(
+		[]arrow.Field{
+			{Name: "ts", Type: &arrow.TimestampType{Unit: arrow.Second}, Nullable: true},
+			{Name: "id", Type: arrow.PrimitiveTypes.Int32},
+		}, nil,
+	)
+	result, err := CastRecord(mem, record, targetSchema)
+	if err != nil {
+		t.Fatalf("received unexpected error: %s", err)
+	}
+	defer result.Release()
+
+	if !result.Schema().Equal(targetSchema) {
+		t.Errorf("expected schema %s, got %s", targetSchema, result.Schema())
+	}
+	expectedRows := []string{"2024-03-01 10:00:00Z,1", "(null),2"}
+	if actual := recordRowStrings(result); !slices.Equal(actual, expectedRows) {
+		t.Errorf("expected rows %v, got %v", expectedRows, actual)
+	}
+
+	// the target field is not in the record
+	_, err = CastRecord(mem, record, arrow.NewSchema([]arrow.Field{{Name: "missing", Type: arrow.PrimitiveTypes.Int32}}, nil))
+	if !errors.Is(err, ErrColumnNotFound) {
+		t.Errorf("expected error %v, got %v", ErrColumnNotFound, err)
+	}
+
+	// the first timestamp has a time of day so it is not a whole date
+	_, err = CastRecord(mem, record, arrow.NewSchema([]arrow.Field{{Name: "ts", Type: arrow.FixedWidthTypes.Date32}}, nil))
+	if !errors.Is(err, ErrLossyCast) {
+		t.Errorf("expected error %v, got %v", ErrLossyCast, err)
+	}
+	// the ts column has a null value but the target field is not nullable
+	_, err = CastRecordWithOptions(mem, record, arrow.NewSchema([]arrow.Field{{Name: "ts", Type: arrow.FixedWidthTypes.Date32}}, nil), CastOptions{AllowLossy: true})
+	if !errors.Is(err, ErrNullValuesNotAllowed) {
+		t.Errorf("expected error %v, got %v", ErrNullValuesNotAllowed, err)
+	}
+}
@@ -84,9 +84,81 @@ func compareRecordRowsUsingAllFields(record1, record2 arrow.Record, index1, inde
 	return 0, nil
 }
 
+/*
+Options used by CompareRecordRowsWithOptions. TypeMismatch controls what
+happens when a column has a different data type in each record.
+*/
+type CompareOptions struct {
+	TypeMismatch TypeMismatchMode
+}
+
+type TypeMismatchMode int
+
+const (
+	// return ErrDataTypesNotEqual when the data types are not equal
+	TypeMismatchError TypeMismatchMode = iota
+	// promote compatible data types to a common type before comparing them
+	TypeMismatchPromote
+)
+
+/*
+Determines if the row at index1 in record1 is less/equal/greater than
+the row at index2 in record2 in the same way as CompareRecordRows. Columns
+are matched by name and don't need to have the same data type in both
+records. When the types are not equal the values are either promoted to a
+common type and compared or ErrDataTypesNotEqual is returned, depending on
+the options. Compatible types are integers of any width or signedness,
+integers and floats, timestamps with different units or time zones, dates
+and timestamps, durations or times with different units, STRING and
+LARGE_STRING, and BINARY and LARGE_BINARY.
+*/
+func CompareRecordRowsWithOptions(record1, record2 arrow.Record, index1, index2 int, opts CompareOptions, fields ...string) (int, error) {
+
+	if record1.NumRows() <= int64(index1) {
+		return 0, errs.NewStackError(fmt.Errorf("%w| index1 value of %d out of bounds %d", ErrIndexOutOfBounds, index1, record1.NumRows()))
+	}
+	if record2.NumRows() <= int64(index2) {
+		return 0, errs.NewStackError(fmt.Errorf("%w| index2 value of %d out of bounds %d", ErrIndexOutOfBounds, index2, record2.NumRows()))
+	}
+	if len(fields) == 0 {
+		for i := 0; i < int(record1.NumCols()); i++ {
+			fields = append(fields, record1.ColumnName(i))
+		}
+	}
+
+	for _, field := range fields {
+		column1Idxs := record1.Schema().FieldIndices(field)
+		column2Idxs := record2.Schema().FieldIndices(field)
+		if len(column1Idxs) == 0 || len(column2Idxs) == 0 {
+			return 0, errs.NewStackError(fmt.Errorf("%w| column name: %s", ErrColumnNotFound, field))
+		}
+		for _, column1Idx := range column1Idxs {
+			for _, column2Idx := range column2Idxs {
+				column1 := record1.Column(column1Idx)
+				column2 := record2.Column(column2Idx)
+
+				var compareValue int
+				var err error
+				if opts.TypeMismatch == TypeMismatchPromote {
+					compareValue, err = comparePromotedArrayValues(column1, column2, index1, index2)
+				} else {
+					compareValue, err = compareArrayValues(column1, column2, index1, index2)
+				}
+				if err != nil {
+					return 0, errs.Wrap(err, fmt.Errorf("failed to compare column %s", field))
+				}
+				if compareValue != 0 {
+					return compareValue, nil
+				}
+			}
+		}
+	}
+	return 0, nil
+}
+
 func compareArrayValues(a1, a2 arrow.Array, i1, i2 int) (int, error) {
-	if a1.DataType().ID() != a2.DataType().ID() {
-		return 0, nil
+	if !arrow.TypeEqual(a1.DataType(), a2.DataType()) {
+		return 0, errs.NewStackError(fmt.Errorf("%w| %s and %s", ErrDataTypesNotEqual, a1.DataType(), a2.DataType()))
 	}
 
 	if a1.IsNull(i1) && a2.IsNull(i2) {
@@ -124,8 +196,12 @@ func compareArrayValues(a1, a2 arrow.Array, i1, i2 int) (int, error) {
 		return nativeArrayValuesEqual[float64, *array.Float64](a1.(*array.Float64), a2.(*array.Float64), i1, i2), nil
 	case arrow.STRING:
 		return nativeArrayValuesEqual[string, *array.String](a1.(*array.String), a2.(*array.String), i1, i2), nil
+	case arrow.LARGE_STRING:
+		return nativeArrayValuesEqual[string, *array.LargeString](a1.(*array.LargeString), a2.(*array.LargeString), i1, i2), nil
 	case arrow.BINARY:
 		return binaryArrayEqual(a1.(*array.Binary), a2.(*array.Binary), i1, i2), nil
+	case arrow.LARGE_BINARY:
+		return bytes.Compare(a1.(*array.LargeBinary).Value(i1), a2.(*array.LargeBinary).Value(i2)), nil
 	case arrow.DATE32:
 		return nativeArrayValuesEqual[arrow.Date32, *array.Date32](a1.(*array.Date32), a2.(*array.Date32), i1, i2), nil
 	case arrow.DATE64:
@@ -148,7 +224,9 @@ func nativeArrayValuesEqual[T cmp.Ordered, E valueArray[T]](a1, a2 E, i1, i2 int
 }
 
 func float16ArrayValuesEqual(a1, a2 *array.Float16, i1, i2 int) int {
-	return a1.Value(i1).Cmp(a2.Value(i2))
+	// compare as float32 values, which are exact, so NaN values are equal to
+	// each other and less than any other value like the other float types
+	return cmp.Compare(a1.Value(i1).Float32(), a2.Value(i2).Float32())
 }
 
 func booleanArrayValuesEqual(a1, a2 *array.Boolean, i1, i2 int) int {
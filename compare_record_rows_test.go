@@ -1,6 +1,7 @@
 package arrowops
 
 import (
+	"errors"
 	"fmt"
 	"testing"
 
@@ -184,3 +185,140 @@ func TestCompareRecordRows(t *testing.T) {
 	}
 
 }
+
+func TestCompareRecordRowsWithOptions(t *testing.T) {
+
+	mem := memory.NewGoAllocator()
+
+	record1Bldr := func() arrow.Record {
+		recBuilder := array.NewRecordBuilder(
+			mem, arrow.NewSchema(
+				[]arrow.Field{
+					{Name: "a", Type: arrow.PrimitiveTypes.Int32},
+					{Name: "b", Type: arrow.BinaryTypes.String},
+					{Name: "c", Type: arrow.PrimitiveTypes.Int64},
+					{Name: "d", Type: arrow.FixedWidthTypes.Timestamp_s},
+					{Name: "e", Type: arrow.PrimitiveTypes.Int8},
+				}, nil),
+		)
+		defer recBuilder.Release()
+
+		recBuilder.Field(0).(*array.Int32Builder).AppendValues([]int32{1, 2, -3}, nil)
+		recBuilder.Field(1).(*array.StringBuilder).AppendValues([]string{"a", "b", "c"}, nil)
+		recBuilder.Field(2).(*array.Int64Builder).AppendValues([]int64{1, 2, 3}, nil)
+		recBuilder.Field(3).(*array.TimestampBuilder).AppendValues([]arrow.Timestamp{1, 2, 3}, nil)
+		recBuilder.Field(4).(*array.Int8Builder).AppendValues([]int8{-1, 0, 1}, nil)
+		return recBuilder.NewRecord()
+	}
+	record2Bldr := func() arrow.Record {
+		recBuilder := array.NewRecordBuilder(
+			mem, arrow.NewSchema(
+				[]arrow.Field{
+					{Name: "a", Type: arrow.PrimitiveTypes.Int64},
+					{Name: "b", Type: arrow.BinaryTypes.LargeString},
+					{Name: "c", Type: arrow.PrimitiveTypes.Float64},
+					{Name: "d", Type: arrow.FixedWidthTypes.Timestamp_ms},
+					{Name: "e", Type: arrow.PrimitiveTypes.Uint64},
+				}, nil),
+		)
+		defer recBuilder.Release()
+
+		recBuilder.Field(0).(*array.Int64Builder).AppendValues([]int64{1, 77, -3}, nil)
+		recBuilder.Field(1).(*array.LargeStringBuilder).AppendValues([]string{"a", "a", "d"}, nil)
+		recBuilder.Field(2).(*array.Float64Builder).AppendValues([]float64{1.0, 2.5, 2.5}, nil)
+		recBuilder.Field(3).(*array.TimestampBuilder).AppendValues([]arrow.Timestamp{1000, 1999, 3001}, nil)
+		recBuilder.Field(4).(*array.Uint64Builder).AppendValues([]uint64{1, 0, 1 << 63}, nil)
+		return recBuilder.NewRecord()
+	}
+
+	testCases := []struct {
+		caseName    string
+		opts        CompareOptions
+		fields      []string
+		expectedVal []int
+		expectedErr error
+	}{
+		{
+			caseName:    "integers_of_different_widths",
+			opts:        CompareOptions{TypeMismatch: TypeMismatchPromote},
+			fields:      []string{"a"},
+			expectedVal: []int{0, -1, 0},
+		},
+		{
+			caseName:    "string_and_large_string",
+			opts:        CompareOptions{TypeMismatch: TypeMismatchPromote},
+			fields:      []string{"b"},
+			expectedVal: []int{0, 1, -1},
+		},
+		{
+			caseName:    "integer_and_float",
+			opts:        CompareOptions{TypeMismatch: TypeMismatchPromote},
+			fields:      []string{"c"},
+			expectedVal: []int{0, -1, 1},
+		},
+		{
+			caseName:    "timestamp_units",
+			opts:        CompareOptions{TypeMismatch: TypeMismatchPromote},
+			fields:      []string{"d"},
+			expectedVal: []int{0, 1, -1},
+		},
+		{
+			caseName:    "signed_and_unsigned",
+			opts:        CompareOptions{TypeMismatch: TypeMismatchPromote},
+			fields:      []string{"e"},
+			expectedVal: []int{-1, 0, -1},
+		},
+		{
+			caseName:    "multiple_columns",
+			opts:        CompareOptions{TypeMismatch: TypeMismatchPromote},
+			fields:      []string{"a", "b"},
+			expectedVal: []int{0, -1, -1},
+		},
+		{
+			caseName:    "mismatch_error",
+			opts:        CompareOptions{TypeMismatch: TypeMismatchError},
+			fields:      []string{"a"},
+			expectedVal: []int{0, 0, 0},
+			expectedErr: ErrDataTypesNotEqual,
+		},
+	}
+
+	for idx, tc := range testCases {
+		t.Run(fmt.Sprintf("case_%d:%s", idx, tc.caseName), func(t *testing.T) {
+			record1 := record1Bldr()
+			record2 := record2Bldr()
+			defer record1.Release()
+			defer record2.Release()
+			for i := 0; i < int(record1.NumRows()); i++ {
+				val, err := CompareRecordRowsWithOptions(record1, record2, i, i, tc.opts, tc.fields...)
+				if !errors.Is(err, tc.expectedErr) {
+					t.Errorf("[%d] expected error %v, got %v", i, tc.expectedErr, err)
+				}
+				if val != tc.expectedVal[i] {
+					t.Errorf("[%d] expected value %d, got %d", i, tc.expectedVal[i], val)
+				}
+			}
+		})
+	}
+
+}
+
+func TestComparePromotedArrayValuesIncompatibleTypes(t *testing.T) {
+	mem := memory.NewGoAllocator()
+
+	b1 := array.NewInt64Builder(mem)
+	defer b1.Release()
+	b1.Append(1)
+	a1 := b1.NewArray()
+	defer a1.Release()
+
+	b2 := array.NewStringBuilder(mem)
+	defer b2.Release()
+	b2.Append("1")
+	a2 := b2.NewArray()
+	defer a2.Release()
+
+	if _, err := comparePromotedArrayValues(a1, a2, 0, 0); !errors.Is(err, ErrDataTypesNotEqual) {
+		t.Errorf("expected error %v, got %v", ErrDataTypesNotEqual, err)
+	}
+}
@@ -9,12 +9,43 @@ import (
 	"github.com/apache/arrow/go/v17/arrow/memory"
 )
 
+type DeduplicateStrategy int
+
+const (
+	// sort the record by the columns and keep the first row of each group
+	DeduplicateSort DeduplicateStrategy = iota
+	// find the first row of each group using a hash table, no sorting required
+	DeduplicateHash
+)
+
+/*
+Options used by DeduplicateRecordWithOptions. PresortedByColumnsNames is only
+used by the DeduplicateSort strategy.
+*/
+type DeduplicateOptions struct {
+	Strategy                DeduplicateStrategy
+	PresortedByColumnsNames bool
+}
+
 /*
 Takes a record and deduplicates the rows based on the subset of columns provided.
 The rows are not garanteed to be in any particular order. All columns from the
 input record will be returned in the result record.
 */
 func DeduplicateRecord(mem *memory.GoAllocator, record arrow.Record, columns []string, presortedByColumnsNames bool) (arrow.Record, error) {
+	return DeduplicateRecordWithOptions(mem, record, columns, DeduplicateOptions{
+		Strategy:                DeduplicateSort,
+		PresortedByColumnsNames: presortedByColumnsNames,
+	})
+}
+
+/*
+Takes a record and deduplicates the rows based on the subset of columns provided
+using the strategy in the options. The DeduplicateSort strategy behaves the same as
+DeduplicateRecord. The DeduplicateHash strategy doesn't sort the record, it keeps the
+first occurrence of each group and returns the rows in the same order as the input.
+*/
+func DeduplicateRecordWithOptions(mem *memory.GoAllocator, record arrow.Record, columns []string, opts DeduplicateOptions) (arrow.Record, error) {
 	record.Retain()
 	defer record.Release()
 
@@ -22,6 +53,98 @@ func DeduplicateRecord(mem *memory.GoAllocator, record arrow.Record, columns []s
 		return nil, errs.NewStackError(ErrColumnNamesRequired)
 	}
 
+	switch opts.Strategy {
+	case DeduplicateSort:
+		return sortDeduplicateRecord(mem, record, columns, opts.PresortedByColumnsNames)
+	case DeduplicateHash:
+		return hashDeduplicateRecord(mem, record, columns)
+	default:
+		return nil, errs.NewStackError(fmt.Errorf("unknown deduplicate strategy %d", opts.Strategy))
+	}
+}
+
+type KeepPolicy int
+
+const (
+	// keep the row that sorts first by the ordering keys
+	KeepFirst KeepPolicy = iota
+	// keep the row that sorts last by the ordering keys
+	KeepLast
+)
+
+/*
+Takes a record and deduplicates the rows based on the key columns provided, keeping
+exactly one row for each key. The row kept is the one that sorts first or last by the
+ordering keys, depending on the policy. When multiple rows have equal ordering values
+KeepFirst keeps the earliest of those rows in the input and KeepLast keeps the latest.
+The rows are returned in the order each key first appears in the input record.
+For example, keeping the latest version of each row in change data would use the
+primary key columns, an ordering key on the updated timestamp and KeepLast.
+*/
+func DeduplicateRecordByOrdering(
+	mem *memory.GoAllocator,
+	record arrow.Record,
+	keyColumns []string,
+	orderKeys []SortKey,
+	policy KeepPolicy,
+) (arrow.Record, error) {
+	record.Retain()
+	defer record.Release()
+
+	if len(keyColumns) == 0 || len(orderKeys) == 0 {
+		return nil, errs.NewStackError(ErrColumnNamesRequired)
+	}
+
+	orderComparator, err := NewRowComparator(record.Schema(), record.Schema(), orderKeys)
+	if err != nil {
+		return nil, errs.Wrap(err, fmt.Errorf("failed to create comparator for the ordering keys"))
+	}
+	groups, err := hashGroupRows(record, keyColumns)
+	if err != nil {
+		return nil, errs.Wrap(err, fmt.Errorf("failed to group record by columns: %v", keyColumns))
+	}
+
+	winners := make([]uint32, groups.numGroups())
+	copy(winners, groups.firstRows)
+	for i := 0; i < int(record.NumRows()); i++ {
+		group := groups.groupIDs[i]
+		cmpRow := orderComparator.Compare(record, record, i, int(winners[group]))
+		switch policy {
+		case KeepFirst:
+			if cmpRow < 0 {
+				winners[group] = uint32(i)
+			}
+		case KeepLast:
+			if cmpRow >= 0 {
+				winners[group] = uint32(i)
+			}
+		default:
+			return nil, errs.NewStackError(fmt.Errorf("unknown keep policy %d", policy))
+		}
+	}
+
+	deduplicatedRecord, err := takeRecordRows(mem, record, winners)
+	if err != nil {
+		return nil, errs.Wrap(err, fmt.Errorf("failed to take %d rows from record", len(winners)))
+	}
+	return deduplicatedRecord, nil
+}
+
+func hashDeduplicateRecord(mem *memory.GoAllocator, record arrow.Record, columns []string) (arrow.Record, error) {
+	groups, err := hashGroupRows(record, columns)
+	if err != nil {
+		return nil, errs.Wrap(err, fmt.Errorf("failed to group record by columns: %v", columns))
+	}
+
+	deduplicatedRecord, err := takeRecordRows(mem, record, groups.firstRows)
+	if err != nil {
+		return nil, errs.Wrap(err, fmt.Errorf("failed to take %d rows from record", len(groups.firstRows)))
+	}
+	return deduplicatedRecord, nil
+}
+
+func sortDeduplicateRecord(mem *memory.GoAllocator, record arrow.Record, columns []string, presortedByColumnsNames bool) (arrow.Record, error) {
+
 	var sortedRecord arrow.Record
 	if !presortedByColumnsNames {
 		r, err := SortRecord(mem, record, columns)
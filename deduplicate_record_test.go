@@ -129,3 +129,248 @@ func TestDeduplicateRecord(t *testing.T) {
 	}
 
 }
+
+func BenchmarkDeduplicateRecordUsingHashStrategyRandomData(b *testing.B) {
+	for _, size := range TEST_SIZES {
+		b.Run(fmt.Sprintf("size=%d", size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				mem := memory.NewGoAllocator()
+				r1 := MockData(mem, size, "random")
+				defer r1.Release()
+				b.StartTimer()
+				val, err := DeduplicateRecordWithOptions(mem, r1, []string{"a"}, DeduplicateOptions{Strategy: DeduplicateHash})
+				if err != nil {
+					b.Fatalf("received error while deduplicating record '%s'", err)
+				}
+				val.Release()
+			}
+		})
+	}
+}
+
+func TestDeduplicateRecordWithHashStrategy(t *testing.T) {
+
+	mem := memory.NewGoAllocator()
+
+	schema := arrow.NewSchema(
+		[]arrow.Field{
+			{Name: "a", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+			{Name: "b", Type: arrow.BinaryTypes.String},
+			{Name: "c", Type: arrow.PrimitiveTypes.Float64},
+		}, nil)
+
+	testCases := []struct {
+		caseName           string
+		recordBldr         func() arrow.Record
+		columns            []string
+		expectedRecordBldr func() arrow.Record
+		expectedErr        error
+	}{
+		{
+			caseName: "keeps_first_occurrence_in_input_order",
+			recordBldr: func() arrow.Record {
+				recBuilder := array.NewRecordBuilder(mem, schema)
+				defer recBuilder.Release()
+
+				recBuilder.Field(0).(*array.Int64Builder).AppendValues([]int64{3, 1, 3, 2, 1}, nil)
+				recBuilder.Field(1).(*array.StringBuilder).AppendValues([]string{"c", "a", "c", "b", "a"}, nil)
+				recBuilder.Field(2).(*array.Float64Builder).AppendValues([]float64{3.3, 1.1, 3.4, 2.2, 1.2}, nil)
+				return recBuilder.NewRecord()
+			},
+			columns: []string{"a", "b"},
+			expectedRecordBldr: func() arrow.Record {
+				recBuilder := array.NewRecordBuilder(mem, schema)
+				defer recBuilder.Release()
+
+				recBuilder.Field(0).(*array.Int64Builder).AppendValues([]int64{3, 1, 2}, nil)
+				recBuilder.Field(1).(*array.StringBuilder).AppendValues([]string{"c", "a", "b"}, nil)
+				recBuilder.Field(2).(*array.Float64Builder).AppendValues([]float64{3.3, 1.1, 2.2}, nil)
+				return recBuilder.NewRecord()
+			},
+		},
+		{
+			caseName: "null_keys_are_grouped_together",
+			recordBldr: func() arrow.Record {
+				recBuilder := array.NewRecordBuilder(mem, schema)
+				defer recBuilder.Release()
+
+				recBuilder.Field(0).(*array.Int64Builder).AppendValues([]int64{0, 1, 0}, []bool{false, true, false})
+				recBuilder.Field(1).(*array.StringBuilder).AppendValues([]string{"x", "y", "z"}, nil)
+				recBuilder.Field(2).(*array.Float64Builder).AppendValues([]float64{1.0, 2.0, 3.0}, nil)
+				return recBuilder.NewRecord()
+			},
+			columns: []string{"a"},
+			expectedRecordBldr: func() arrow.Record {
+				recBuilder := array.NewRecordBuilder(mem, schema)
+				defer recBuilder.Release()
+
+				recBuilder.Field(0).(*array.Int64Builder).AppendValues([]int64{0, 1}, []bool{false, true})
+				recBuilder.Field(1).(*array.StringBuilder).AppendValues([]string{"x", "y"}, nil)
+				recBuilder.Field(2).(*array.Float64Builder).AppendValues([]float64{1.0, 2.0}, nil)
+				return recBuilder.NewRecord()
+			},
+		},
+		{
+			caseName: "no_columns",
+			recordBldr: func() arrow.Record {
+				return MockData(mem, 10, "ascending")
+			},
+			columns:     []string{},
+			expectedErr: ErrColumnNamesRequired,
+		},
+	}
+
+	for idx, tc := range testCases {
+		t.Run(fmt.Sprintf("case_%d:%s", idx, tc.caseName), func(t *testing.T) {
+
+			record := tc.recordBldr()
+			defer record.Release()
+
+			actualRecord, err := DeduplicateRecordWithOptions(mem, record, tc.columns, DeduplicateOptions{Strategy: DeduplicateHash})
+			if !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("expected error: %v, got: %v", tc.expectedErr, err)
+			}
+			if err != nil {
+				return
+			}
+			defer actualRecord.Release()
+
+			expectedRecord := tc.expectedRecordBldr()
+			defer expectedRecord.Release()
+
+			diff, err := DiffRecords(expectedRecord, actualRecord, DiffOptions{})
+			if err != nil {
+				t.Fatalf("received unexpected error: %s", err)
+			}
+			if !diff.Equal() {
+				t.Errorf("expected records to be equal:\n%s", diff)
+			}
+		})
+	}
+
+}
+
+func TestDeduplicateRecordStrategiesAgree(t *testing.T) {
+	mem := memory.NewGoAllocator()
+
+	record := MockData(mem, 1_000, "random")
+	defer record.Release()
+
+	sortedRecord, err := DeduplicateRecordWithOptions(mem, record, []string{"a"}, DeduplicateOptions{Strategy: DeduplicateSort})
+	if err != nil {
+		t.Fatalf("received unexpected error: %s", err)
+	}
+	defer sortedRecord.Release()
+
+	hashedRecord, err := DeduplicateRecordWithOptions(mem, record, []string{"a"}, DeduplicateOptions{Strategy: DeduplicateHash})
+	if err != nil {
+		t.Fatalf("received unexpected error: %s", err)
+	}
+	defer hashedRecord.Release()
+
+	equal, err := RecordsEqualUnordered(sortedRecord, hashedRecord)
+	if err != nil {
+		t.Fatalf("received unexpected error: %s", err)
+	}
+	if !equal {
+		t.Errorf("expected both strategies to return the same rows")
+	}
+}
+
+func TestDeduplicateRecordByOrdering(t *testing.T) {
+
+	mem := memory.NewGoAllocator()
+
+	schema := arrow.NewSchema(
+		[]arrow.Field{
+			{Name: "id", Type: arrow.PrimitiveTypes.Int64},
+			{Name: "updated_at", Type: arrow.FixedWidthTypes.Timestamp_ms, Nullable: true},
+			{Name: "value", Type: arrow.BinaryTypes.String},
+		}, nil)
+
+	recordBldr := func() arrow.Record {
+		recBuilder := array.NewRecordBuilder(mem, schema)
+		defer recBuilder.Release()
+
+		recBuilder.Field(0).(*array.Int64Builder).AppendValues([]int64{1, 2, 1, 1, 2, 3}, nil)
+		recBuilder.Field(1).(*array.TimestampBuilder).AppendValues(
+			[]arrow.Timestamp{10, 5, 30, 30, 1, 0},
+			[]bool{true, true, true, true, true, false},
+		)
+		recBuilder.Field(2).(*array.StringBuilder).AppendValues([]string{"1a", "2a", "1b", "1c", "2b", "3a"}, nil)
+		return recBuilder.NewRecord()
+	}
+
+	testCases := []struct {
+		caseName       string
+		orderKeys      []SortKey
+		policy         KeepPolicy
+		expectedValues []string
+		expectedErr    error
+	}{
+		{
+			caseName:       "keep_latest",
+			orderKeys:      []SortKey{{Column: "updated_at"}},
+			policy:         KeepLast,
+			expectedValues: []string{"1c", "2a", "3a"},
+		},
+		{
+			caseName:       "keep_earliest",
+			orderKeys:      []SortKey{{Column: "updated_at"}},
+			policy:         KeepFirst,
+			expectedValues: []string{"1a", "2b", "3a"},
+		},
+		{
+			caseName:       "keep_first_descending_breaks_ties_by_input_order",
+			orderKeys:      []SortKey{{Column: "updated_at", Descending: true}},
+			policy:         KeepFirst,
+			expectedValues: []string{"1b", "2a", "3a"},
+		},
+		{
+			caseName:       "multiple_ordering_keys",
+			orderKeys:      []SortKey{{Column: "updated_at"}, {Column: "value", Descending: true}},
+			policy:         KeepLast,
+			expectedValues: []string{"1b", "2a", "3a"},
+		},
+		{
+			caseName:    "no_ordering_keys",
+			orderKeys:   []SortKey{},
+			policy:      KeepLast,
+			expectedErr: ErrColumnNamesRequired,
+		},
+		{
+			caseName:    "unknown_ordering_column",
+			orderKeys:   []SortKey{{Column: "z"}},
+			policy:      KeepLast,
+			expectedErr: ErrColumnNotFound,
+		},
+	}
+
+	for idx, tc := range testCases {
+		t.Run(fmt.Sprintf("case_%d:%s", idx, tc.caseName), func(t *testing.T) {
+			record := recordBldr()
+			defer record.Release()
+
+			actualRecord, err := DeduplicateRecordByOrdering(mem, record, []string{"id"}, tc.orderKeys, tc.policy)
+			if !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("expected error: %v, got: %v", tc.expectedErr, err)
+			}
+			if err != nil {
+				return
+			}
+			defer actualRecord.Release()
+
+			values := actualRecord.Column(2).(*array.String)
+			if values.Len() != len(tc.expectedValues) {
+				t.Fatalf("expected %d rows, got %d", len(tc.expectedValues), values.Len())
+			}
+			for i, expectedValue := range tc.expectedValues {
+				if values.Value(i) != expectedValue {
+					t.Errorf("[%d] expected value %s, got %s", i, expectedValue, values.Value(i))
+				}
+			}
+		})
+	}
+
+}
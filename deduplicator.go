@@ -0,0 +1,175 @@
+package arrowops
+
+import (
+	"fmt"
+
+	"github.com/alekLukanen/errs"
+	"github.com/apache/arrow/go/v17/arrow"
+	"github.com/apache/arrow/go/v17/arrow/array"
+	"github.com/apache/arrow/go/v17/arrow/memory"
+)
+
+const (
+	deduplicatorStateColumn      = "key"
+	deduplicatorStateMetadataKey = "arrowops:deduplicator_key_schema"
+)
+
+/*
+Options used by the Deduplicator. When MaxSeenKeys is greater than zero only
+that many keys are remembered, once the limit is reached the oldest keys are
+forgotten. Rows with a forgotten key will be emitted again if they are seen
+later, so the limit trades exactness for bounded memory.
+*/
+type DeduplicatorOptions struct {
+	MaxSeenKeys int
+}
+
+/*
+Deduplicates rows across a sequence of records. Each call to Deduplicate returns
+the rows from the record whose key has not been seen in any earlier record or
+earlier in the same record. Keys are stored as encoded rows so they are compared
+exactly, null values are equal to other null values.
+*/
+type Deduplicator struct {
+	mem     *memory.GoAllocator
+	columns []string
+	encoder *RowEncoder
+	opts    DeduplicatorOptions
+
+	seen map[string]struct{}
+	// keys in the order they were first seen, starting at orderStart
+	order      []string
+	orderStart int
+}
+
+/*
+Creates a deduplicator for records with the schema provided using the key columns.
+*/
+func NewDeduplicator(mem *memory.GoAllocator, schema *arrow.Schema, columns []string, opts DeduplicatorOptions) (*Deduplicator, error) {
+	if len(columns) == 0 {
+		return nil, errs.NewStackError(ErrColumnNamesRequired)
+	}
+	encoder, err := NewRowEncoder(schema, SortKeysFromColumns(columns...))
+	if err != nil {
+		return nil, errs.Wrap(err, fmt.Errorf("failed to create row encoder for columns %v", columns))
+	}
+	return &Deduplicator{
+		mem:     mem,
+		columns: columns,
+		encoder: encoder,
+		opts:    opts,
+		seen:    make(map[string]struct{}),
+		order:   make([]string, 0),
+	}, nil
+}
+
+/*
+Returns the rows of the record whose key has not been seen before, in the
+same order as the input record. The keys of the returned rows are remembered.
+*/
+func (d *Deduplicator) Deduplicate(record arrow.Record) (arrow.Record, error) {
+	record.Retain()
+	defer record.Release()
+
+	// the key columns are found by name so they can be at
+	// different positions than in the deduplicator schema
+	columnIdxs, err := d.encoder.recordColumnIndices(record.Schema())
+	if err != nil {
+		return nil, errs.Wrap(err, fmt.Errorf("record schema does not match the deduplicator schema for columns %v", d.columns))
+	}
+
+	rowIndices := make([]uint32, 0)
+	var key []byte
+	for i := 0; i < int(record.NumRows()); i++ {
+		key = d.encoder.appendRow(key[:0], record, i, columnIdxs)
+		if _, ok := d.seen[string(key)]; ok {
+			continue
+		}
+		d.addKey(string(key))
+		rowIndices = append(rowIndices, uint32(i))
+	}
+
+	deduplicatedRecord, err := takeRecordRows(d.mem, record, rowIndices)
+	if err != nil {
+		return nil, errs.Wrap(err, fmt.Errorf("failed to take %d rows from record", len(rowIndices)))
+	}
+	return deduplicatedRecord, nil
+}
+
+/*
+The number of keys currently remembered.
+*/
+func (d *Deduplicator) NumSeenKeys() int {
+	return len(d.seen)
+}
+
+/*
+Exports the remembered keys as a record with a single BINARY column, ordered from
+the oldest to the newest key. The record can be persisted, for example with
+WriteRecordToParquetFile, and loaded into another deduplicator with ImportState.
+*/
+func (d *Deduplicator) ExportState() (arrow.Record, error) {
+	b := array.NewBinaryBuilder(d.mem, arrow.BinaryTypes.Binary)
+	defer b.Release()
+	b.Reserve(len(d.seen))
+	for _, key := range d.order[d.orderStart:] {
+		b.AppendString(key)
+	}
+	keys := b.NewBinaryArray()
+	defer keys.Release()
+
+	metadata := arrow.NewMetadata([]string{deduplicatorStateMetadataKey}, []string{d.encoder.Schema().String()})
+	schema := arrow.NewSchema([]arrow.Field{{Name: deduplicatorStateColumn, Type: arrow.BinaryTypes.Binary}}, &metadata)
+	return array.NewRecord(schema, []arrow.Array{keys}, int64(keys.Len())), nil
+}
+
+/*
+Adds the keys from a record created by ExportState to the remembered keys. The
+state must have been exported by a deduplicator with the same key columns.
+*/
+func (d *Deduplicator) ImportState(state arrow.Record) error {
+	state.Retain()
+	defer state.Release()
+
+	keySchema, ok := state.Schema().Metadata().GetValue(deduplicatorStateMetadataKey)
+	if !ok || keySchema != d.encoder.Schema().String() {
+		return errs.NewStackError(fmt.Errorf("%w| state was not exported for columns %v", ErrSchemasNotEqual, d.columns))
+	}
+	columnIdxs := state.Schema().FieldIndices(deduplicatorStateColumn)
+	if len(columnIdxs) != 1 {
+		return errs.NewStackError(fmt.Errorf("%w| column name: %s", ErrColumnNotFound, deduplicatorStateColumn))
+	}
+	keys, ok := state.Column(columnIdxs[0]).(*array.Binary)
+	if !ok {
+		return errs.NewStackError(fmt.Errorf("%w| expected BINARY column %s", ErrUnsupportedDataType, deduplicatorStateColumn))
+	}
+	if keys.NullN() > 0 {
+		return errs.NewStackError(fmt.Errorf("%w| null values are not allowed in the state", ErrNullValuesNotAllowed))
+	}
+
+	for i := 0; i < keys.Len(); i++ {
+		key := keys.ValueString(i)
+		if _, ok := d.seen[key]; ok {
+			continue
+		}
+		d.addKey(key)
+	}
+	return nil
+}
+
+func (d *Deduplicator) addKey(key string) {
+	d.seen[key] = struct{}{}
+	d.order = append(d.order, key)
+
+	if d.opts.MaxSeenKeys > 0 && len(d.seen) > d.opts.MaxSeenKeys {
+		delete(d.seen, d.order[d.orderStart])
+		d.order[d.orderStart] = ""
+		d.orderStart++
+	}
+
+	// reclaim the space used by forgotten keys
+	if d.orderStart > 0 && d.orderStart >= len(d.order)/2 {
+		d.order = append(make([]string, 0, len(d.order)-d.orderStart), d.order[d.orderStart:]...)
+		d.orderStart = 0
+	}
+}
@@ -0,0 +1,187 @@
+package arrowops
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/apache/arrow/go/v17/arrow"
+	"github.com/apache/arrow/go/v17/arrow/memory"
+)
+
+func TestDeduplicator(t *testing.T) {
+
+	mem := memory.NewGoAllocator()
+
+	schema := arrow.NewSchema(
+		[]arrow.Field{
+			{Name: "id", Type: arrow.PrimitiveTypes.Int64},
+			{Name: "value", Type: arrow.BinaryTypes.String},
+		}, nil)
+
+	testCases := []struct {
+		caseName       string
+		opts           DeduplicatorOptions
+		batches        []string
+		expectedValues [][]string
+		expectedKeys   int
+	}{
+		{
+			caseName: "duplicates_across_batches",
+			batches: []string{
+				`[{"id": 1, "value": "0:0"}, {"id": 2, "value": "0:1"}, {"id": 1, "value": "0:2"}]`,
+				`[{"id": 2, "value": "1:0"}, {"id": 3, "value": "1:1"}]`,
+				`[{"id": 1, "value": "2:0"}, {"id": 3, "value": "2:1"}, {"id": 4, "value": "2:2"}]`,
+			},
+			expectedValues: [][]string{
+				{"0:0", "0:1"},
+				{"1:1"},
+				{"2:2"},
+			},
+			expectedKeys: 4,
+		},
+		{
+			caseName: "bounded_memory_forgets_oldest_keys",
+			opts:     DeduplicatorOptions{MaxSeenKeys: 2},
+			batches: []string{
+				`[{"id": 1, "value": "0:0"}, {"id": 2, "value": "0:1"}, {"id": 3, "value": "0:2"}]`,
+				`[{"id": 1, "value": "1:0"}, {"id": 3, "value": "1:1"}]`,
+			},
+			expectedValues: [][]string{
+				{"0:0", "0:1", "0:2"},
+				{"1:0"},
+			},
+			expectedKeys: 2,
+		},
+	}
+
+	for idx, tc := range testCases {
+		t.Run(fmt.Sprintf("case_%d:%s", idx, tc.caseName), func(t *testing.T) {
+			var deduplicator *Deduplicator
+			for batchIdx, batch := range tc.batches {
+				record := recordFromJSON(t, mem, schema, batch)
+				defer record.Release()
+
+				if deduplicator == nil {
+					d, err := NewDeduplicator(mem, record.Schema(), []string{"id"}, tc.opts)
+					if err != nil {
+						t.Fatalf("received unexpected error: %s", err)
+					}
+					deduplicator = d
+				}
+
+				result, err := deduplicator.Deduplicate(record)
+				if err != nil {
+					t.Fatalf("received unexpected error: %s", err)
+				}
+				defer result.Release()
+
+				if actual := arrayValueStrings(result.Column(1)); !slices.Equal(actual, tc.expectedValues[batchIdx]) {
+					t.Errorf("[%d] expected values %v, got %v", batchIdx, tc.expectedValues[batchIdx], actual)
+				}
+			}
+			if deduplicator.NumSeenKeys() != tc.expectedKeys {
+				t.Errorf("expected %d seen keys, got %d", tc.expectedKeys, deduplicator.NumSeenKeys())
+			}
+		})
+	}
+
+}
+
+func TestDeduplicatorWithReorderedColumns(t *testing.T) {
+	mem := memory.NewGoAllocator()
+
+	schema := arrow.NewSchema(
+		[]arrow.Field{
+			{Name: "id", Type: arrow.PrimitiveTypes.Int64},
+			{Name: "value", Type: arrow.BinaryTypes.String},
+		}, nil)
+	record1 := recordFromJSON(t, mem, schema, `[{"id": 1, "value": "a"}, {"id": 2, "value": "b"}]`)
+	defer record1.Release()
+	record2 := recordFromJSON(t, mem, schema, `[{"id": 2, "value": "c"}, {"id": 3, "value": "d"}]`)
+	defer record2.Release()
+	reordered, err := TakeRecordColumns(record2, []string{"value", "id"})
+	if err != nil {
+		t.Fatalf("received unexpected error: %s", err)
+	}
+	defer reordered.Release()
+
+	deduplicator, err := NewDeduplicator(mem, record1.Schema(), []string{"id"}, DeduplicatorOptions{})
+	if err != nil {
+		t.Fatalf("received unexpected error: %s", err)
+	}
+	result1, err := deduplicator.Deduplicate(record1)
+	if err != nil {
+		t.Fatalf("received unexpected error: %s", err)
+	}
+	defer result1.Release()
+	result2, err := deduplicator.Deduplicate(reordered)
+	if err != nil {
+		t.Fatalf("received unexpected error: %s", err)
+	}
+	defer result2.Release()
+
+	if actual := arrayValueStrings(result2.Column(0)); !slices.Equal(actual, []string{"d"}) {
+		t.Errorf("expected values %v, got %v", []string{"d"}, actual)
+	}
+}
+
+func TestDeduplicatorExportAndImportState(t *testing.T) {
+	mem := memory.NewGoAllocator()
+
+	schema := arrow.NewSchema(
+		[]arrow.Field{
+			{Name: "id", Type: arrow.PrimitiveTypes.Int64},
+			{Name: "value", Type: arrow.BinaryTypes.String},
+		}, nil)
+	record1 := recordFromJSON(t, mem, schema, `[{"id": 1, "value": "a"}, {"id": 2, "value": "b"}]`)
+	defer record1.Release()
+	record2 := recordFromJSON(t, mem, schema, `[{"id": 2, "value": "c"}, {"id": 3, "value": "d"}, {"id": 1, "value": "e"}]`)
+	defer record2.Release()
+
+	deduplicator1, err := NewDeduplicator(mem, record1.Schema(), []string{"id"}, DeduplicatorOptions{})
+	if err != nil {
+		t.Fatalf("received unexpected error: %s", err)
+	}
+	result1, err := deduplicator1.Deduplicate(record1)
+	if err != nil {
+		t.Fatalf("received unexpected error: %s", err)
+	}
+	defer result1.Release()
+
+	state, err := deduplicator1.ExportState()
+	if err != nil {
+		t.Fatalf("received unexpected error: %s", err)
+	}
+	defer state.Release()
+	if state.NumRows() != 2 {
+		t.Fatalf("expected 2 keys in the state, got %d", state.NumRows())
+	}
+
+	deduplicator2, err := NewDeduplicator(mem, record1.Schema(), []string{"id"}, DeduplicatorOptions{})
+	if err != nil {
+		t.Fatalf("received unexpected error: %s", err)
+	}
+	if err := deduplicator2.ImportState(state); err != nil {
+		t.Fatalf("received unexpected error: %s", err)
+	}
+	result2, err := deduplicator2.Deduplicate(record2)
+	if err != nil {
+		t.Fatalf("received unexpected error: %s", err)
+	}
+	defer result2.Release()
+
+	if actual := arrayValueStrings(result2.Column(1)); !slices.Equal(actual, []string{"d"}) {
+		t.Errorf("expected values %v, got %v", []string{"d"}, actual)
+	}
+
+	// state from a deduplicator using other key columns can't be imported
+	deduplicator3, err := NewDeduplicator(mem, record1.Schema(), []string{"value"}, DeduplicatorOptions{})
+	if err != nil {
+		t.Fatalf("received unexpected error: %s", err)
+	}
+	if err := deduplicator3.ImportState(state); !errors.Is(err, ErrSchemasNotEqual) {
+		t.Errorf("expected error %v, got %v", ErrSchemasNotEqual, err)
+	}
+}
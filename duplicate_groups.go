@@ -0,0 +1,126 @@
+package arrowops
+
+import (
+	"fmt"
+
+	"github.com/alekLukanen/errs"
+	"github.com/apache/arrow/go/v17/arrow"
+	"github.com/apache/arrow/go/v17/arrow/array"
+	"github.com/apache/arrow/go/v17/arrow/memory"
+)
+
+const (
+	DuplicateGroupsCountColumn         = "count"
+	DuplicateGroupsFirstRowIndexColumn = "first_row_index"
+)
+
+/*
+The duplicate groups of a record. GroupIDs has one value for each row in the
+input record containing the index of the row's group in the Summary record.
+The Summary record has one row for each group, ordered by the key columns,
+containing the key columns followed by a "count" INT64 column with the number
+of rows in the group and a "first_row_index" UINT32 column with the index of the
+first row of the group in the input record. Key columns can not have the
+name of either summary column.
+*/
+type DuplicateGroups struct {
+	GroupIDs *array.Uint32
+	Summary  arrow.Record
+}
+
+func (g *DuplicateGroups) Release() {
+	g.GroupIDs.Release()
+	g.Summary.Release()
+}
+
+/*
+Finds the groups of rows with equal values in the columns provided. The record is
+sorted by the columns and adjacent rows are compared to find the group boundaries,
+in the same way as DeduplicateRecord. Null values are grouped together.
+*/
+func FindDuplicateGroups(mem *memory.GoAllocator, record arrow.Record, columns []string) (*DuplicateGroups, error) {
+	record.Retain()
+	defer record.Release()
+
+	if len(columns) == 0 {
+		return nil, errs.NewStackError(ErrColumnNamesRequired)
+	}
+	for _, column := range columns {
+		if column == DuplicateGroupsCountColumn || column == DuplicateGroupsFirstRowIndexColumn {
+			return nil, errs.NewStackError(fmt.Errorf("%w| key column %s has the name of a summary column", ErrDuplicateColumnName, column))
+		}
+	}
+
+	keys := SortKeysFromColumns(columns...)
+	comparator, err := NewRowComparator(record.Schema(), record.Schema(), keys)
+	if err != nil {
+		return nil, errs.Wrap(err, fmt.Errorf("failed to create comparator for columns %v", columns))
+	}
+	sortedIndices, err := sortRowIndices(record, keys)
+	if err != nil {
+		return nil, errs.Wrap(err, fmt.Errorf("failed to sort record by columns: %v", columns))
+	}
+
+	// the sort is stable so the first row of each group in
+	// sorted order is also the first row in the input record
+	groupIDs := make([]uint32, record.NumRows())
+	firstRows := make([]uint32, 0)
+	counts := make([]int64, 0)
+	for i, rowIdx := range sortedIndices {
+		if i == 0 || !comparator.Equal(record, record, int(sortedIndices[i-1]), int(rowIdx)) {
+			firstRows = append(firstRows, rowIdx)
+			counts = append(counts, 0)
+		}
+		groupIDs[rowIdx] = uint32(len(firstRows) - 1)
+		counts[len(counts)-1]++
+	}
+
+	summary, err := duplicateGroupsSummary(mem, record, columns, firstRows, counts)
+	if err != nil {
+		return nil, err
+	}
+
+	groupIDsBuilder := array.NewUint32Builder(mem)
+	defer groupIDsBuilder.Release()
+	groupIDsBuilder.AppendValues(groupIDs, nil)
+
+	return &DuplicateGroups{
+		GroupIDs: groupIDsBuilder.NewUint32Array(),
+		Summary:  summary,
+	}, nil
+}
+
+func duplicateGroupsSummary(mem *memory.GoAllocator, record arrow.Record, columns []string, firstRows []uint32, counts []int64) (arrow.Record, error) {
+	keyRecord, err := TakeRecordColumns(record, columns)
+	if err != nil {
+		return nil, errs.Wrap(err, fmt.Errorf("failed to take columns %v", columns))
+	}
+	defer keyRecord.Release()
+
+	keys, err := takeRecordRows(mem, keyRecord, firstRows)
+	if err != nil {
+		return nil, errs.Wrap(err, fmt.Errorf("failed to take %d rows from record", len(firstRows)))
+	}
+	defer keys.Release()
+
+	countsBuilder := array.NewInt64Builder(mem)
+	defer countsBuilder.Release()
+	countsBuilder.AppendValues(counts, nil)
+	countsArray := countsBuilder.NewInt64Array()
+	defer countsArray.Release()
+
+	firstRowsBuilder := array.NewUint32Builder(mem)
+	defer firstRowsBuilder.Release()
+	firstRowsBuilder.AppendValues(firstRows, nil)
+	firstRowsArray := firstRowsBuilder.NewUint32Array()
+	defer firstRowsArray.Release()
+
+	fields := append(make([]arrow.Field, 0, keys.NumCols()+2), keys.Schema().Fields()...)
+	fields = append(fields,
+		arrow.Field{Name: DuplicateGroupsCountColumn, Type: arrow.PrimitiveTypes.Int64},
+		arrow.Field{Name: DuplicateGroupsFirstRowIndexColumn, Type: arrow.PrimitiveTypes.Uint32},
+	)
+	arrays := append(make([]arrow.Array, 0, keys.NumCols()+2), keys.Columns()...)
+	arrays = append(arrays, countsArray, firstRowsArray)
+	return array.NewRecord(arrow.NewSchema(fields, nil), arrays, int64(len(firstRows))), nil
+}
@@ -0,0 +1,88 @@
+package arrowops
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/apache/arrow/go/v17/arrow"
+	"github.com/apache/arrow/go/v17/arrow/array"
+	"github.com/apache/arrow/go/v17/arrow/memory"
+)
+
+func TestFindDuplicateGroups(t *testing.T) {
+	mem := memory.NewGoAllocator()
+
+	recBuilder := array.NewRecordBuilder(
+		mem, arrow.NewSchema(
+			[]arrow.Field{
+				{Name: "a", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+				{Name: "b", Type: arrow.BinaryTypes.String},
+			}, nil),
+	)
+	defer recBuilder.Release()
+
+	recBuilder.Field(0).(*array.Int64Builder).AppendValues([]int64{3, 1, 3, 0, 2, 1, 0}, []bool{true, true, true, false, true, true, false})
+	recBuilder.Field(1).(*array.StringBuilder).AppendValues([]string{"a", "b", "c", "d", "e", "f", "g"}, nil)
+	record := recBuilder.NewRecord()
+	defer record.Release()
+
+	groups, err := FindDuplicateGroups(mem, record, []string{"a"})
+	if err != nil {
+		t.Fatalf("received unexpected error: %s", err)
+	}
+	defer groups.Release()
+
+	// groups are ordered by key with the null values first
+	expectedGroupIDs := []uint32{3, 1, 3, 0, 2, 1, 0}
+	if actual := groups.GroupIDs.Uint32Values(); !slices.Equal(actual, expectedGroupIDs) {
+		t.Errorf("expected group ids %v, got %v", expectedGroupIDs, actual)
+	}
+
+	expectedBuilder := array.NewRecordBuilder(
+		mem, arrow.NewSchema(
+			[]arrow.Field{
+				{Name: "a", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+				{Name: DuplicateGroupsCountColumn, Type: arrow.PrimitiveTypes.Int64},
+				{Name: DuplicateGroupsFirstRowIndexColumn, Type: arrow.PrimitiveTypes.Uint32},
+			}, nil),
+	)
+	defer expectedBuilder.Release()
+
+	expectedBuilder.Field(0).(*array.Int64Builder).AppendValues([]int64{0, 1, 2, 3}, []bool{false, true, true, true})
+	expectedBuilder.Field(1).(*array.Int64Builder).AppendValues([]int64{2, 2, 1, 2}, nil)
+	expectedBuilder.Field(2).(*array.Uint32Builder).AppendValues([]uint32{3, 1, 4, 0}, nil)
+	expected := expectedBuilder.NewRecord()
+	defer expected.Release()
+
+	diff, err := DiffRecords(expected, groups.Summary, DiffOptions{})
+	if err != nil {
+		t.Fatalf("received unexpected error: %s", err)
+	}
+	if !diff.Equal() {
+		t.Errorf("expected summary to equal the expected record:\n%s", diff)
+	}
+}
+
+func TestFindDuplicateGroupsErrors(t *testing.T) {
+	mem := memory.NewGoAllocator()
+
+	r1 := MockData(mem, 10, "ascending")
+	defer r1.Release()
+
+	_, err := FindDuplicateGroups(mem, r1, nil)
+	if !errors.Is(err, ErrColumnNamesRequired) {
+		t.Errorf("expected error %v, got %v", ErrColumnNamesRequired, err)
+	}
+	_, err = FindDuplicateGroups(mem, r1, []string{"z"})
+	if !errors.Is(err, ErrColumnNotFound) {
+		t.Errorf("expected error %v, got %v", ErrColumnNotFound, err)
+	}
+
+	renamed := array.NewRecord(arrow.NewSchema([]arrow.Field{{Name: DuplicateGroupsCountColumn, Type: arrow.PrimitiveTypes.Uint32}}, nil), []arrow.Array{r1.Column(0)}, r1.NumRows())
+	defer renamed.Release()
+	_, err = FindDuplicateGroups(mem, renamed, []string{DuplicateGroupsCountColumn})
+	if !errors.Is(err, ErrDuplicateColumnName) {
+		t.Errorf("expected error %v, got %v", ErrDuplicateColumnName, err)
+	}
+}
@@ -1,8 +1,11 @@
 package arrowops
 
 import (
+	"fmt"
+	"math"
 	"slices"
 
+	"github.com/alekLukanen/errs"
 	"github.com/apache/arrow/go/v17/arrow"
 	"github.com/apache/arrow/go/v17/arrow/array"
 )
@@ -22,3 +25,167 @@ func RecordsEqual(rec1, rec2 arrow.Record, fields ...string) bool {
 	}
 	return true
 }
+
+/*
+Checks that both records contain the same rows, with the same number of
+occurrences, regardless of the order of the rows. Only the field/column names
+provided are compared, if none are provided all columns are compared. Null values
+are equal to other null values. False is returned when the records have different
+schemas for the fields being compared.
+*/
+func RecordsEqualUnordered(rec1, rec2 arrow.Record, fields ...string) (bool, error) {
+	if rec1.NumRows() != rec2.NumRows() {
+		return false, nil
+	}
+	if !RecordSchemasEqual(rec1, rec2, fields...) {
+		return false, nil
+	}
+
+	if len(fields) == 0 {
+		for _, field := range rec1.Schema().Fields() {
+			fields = append(fields, field.Name)
+		}
+	}
+	if len(fields) == 0 {
+		return true, nil
+	}
+
+	// each record needs its own encoder since the columns can be in different positions
+	encoder1, err := NewRowEncoder(rec1.Schema(), SortKeysFromColumns(fields...))
+	if err != nil {
+		return false, errs.Wrap(err, fmt.Errorf("failed to create row encoder for fields %v", fields))
+	}
+	encoder2, err := NewRowEncoder(rec2.Schema(), SortKeysFromColumns(fields...))
+	if err != nil {
+		return false, errs.Wrap(err, fmt.Errorf("failed to create row encoder for fields %v", fields))
+	}
+
+	// count the occurrences of each row in the first record and
+	// remove them again using the rows of the second record
+	counts := make(map[string]int, rec1.NumRows())
+	var row []byte
+	for i := 0; i < int(rec1.NumRows()); i++ {
+		row = encoder1.AppendRow(row[:0], rec1, i)
+		counts[string(row)]++
+	}
+	for i := 0; i < int(rec2.NumRows()); i++ {
+		row = encoder2.AppendRow(row[:0], rec2, i)
+		count, ok := counts[string(row)]
+		if !ok {
+			return false, nil
+		}
+		if count == 1 {
+			delete(counts, string(row))
+		} else {
+			counts[string(row)] = count - 1
+		}
+	}
+	return len(counts) == 0, nil
+}
+
+/*
+Options used by RecordsApproxEqual and ArraysApproxEqual. Two floating point
+or decimal values are equal when the absolute difference between them is no
+more than AbsoluteTolerance + RelativeTolerance * max(|value1|, |value2|).
+All other data types are compared exactly.
+*/
+type EqualityOptions struct {
+	AbsoluteTolerance float64
+	RelativeTolerance float64
+	NaNsEqual         bool
+	IgnoreColumnOrder bool
+}
+
+/*
+Compares the two records using the tolerances in the options for FLOAT16, FLOAT32,
+FLOAT64, DECIMAL128 and DECIMAL256 columns. If no field/column names are provided
+all columns are compared. Unless IgnoreColumnOrder is set the columns must be in the
+same position in both records.
+*/
+func RecordsApproxEqual(rec1, rec2 arrow.Record, opts EqualityOptions, fields ...string) bool {
+	if rec1.NumRows() != rec2.NumRows() {
+		return false
+	}
+
+	if len(fields) == 0 {
+		if rec1.NumCols() != rec2.NumCols() {
+			return false
+		}
+		for _, field := range rec1.Schema().Fields() {
+			if !slices.Contains(fields, field.Name) {
+				fields = append(fields, field.Name)
+			}
+		}
+	}
+
+	for _, field := range fields {
+		column1Idxs := rec1.Schema().FieldIndices(field)
+		column2Idxs := rec2.Schema().FieldIndices(field)
+		if len(column1Idxs) != len(column2Idxs) || len(column1Idxs) == 0 {
+			return false
+		}
+		for i := range column1Idxs {
+			if !opts.IgnoreColumnOrder && column1Idxs[i] != column2Idxs[i] {
+				return false
+			}
+			if !ArraysApproxEqual(rec1.Column(column1Idxs[i]), rec2.Column(column2Idxs[i]), opts) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+/*
+Compares the two arrays using the tolerances in the options for FLOAT16, FLOAT32,
+FLOAT64, DECIMAL128 and DECIMAL256 arrays. Arrays of any other data type must be
+exactly equal.
+*/
+func ArraysApproxEqual(a1, a2 arrow.Array, opts EqualityOptions) bool {
+	if !arrow.TypeEqual(a1.DataType(), a2.DataType()) || a1.Len() != a2.Len() {
+		return false
+	}
+
+	var value func(arr arrow.Array, i int) float64
+	switch a1.DataType().ID() {
+	case arrow.FLOAT16:
+		value = func(arr arrow.Array, i int) float64 { return float64(arr.(*array.Float16).Value(i).Float32()) }
+	case arrow.FLOAT32:
+		value = func(arr arrow.Array, i int) float64 { return float64(arr.(*array.Float32).Value(i)) }
+	case arrow.FLOAT64:
+		value = func(arr arrow.Array, i int) float64 { return arr.(*array.Float64).Value(i) }
+	case arrow.DECIMAL128:
+		scale := a1.DataType().(*arrow.Decimal128Type).Scale
+		value = func(arr arrow.Array, i int) float64 { return arr.(*array.Decimal128).Value(i).ToFloat64(scale) }
+	case arrow.DECIMAL256:
+		scale := a1.DataType().(*arrow.Decimal256Type).Scale
+		value = func(arr arrow.Array, i int) float64 { return arr.(*array.Decimal256).Value(i).ToFloat64(scale) }
+	default:
+		return array.Equal(a1, a2)
+	}
+
+	for i := 0; i < a1.Len(); i++ {
+		if a1.IsNull(i) || a2.IsNull(i) {
+			if a1.IsNull(i) != a2.IsNull(i) {
+				return false
+			}
+			continue
+		}
+		if !floatsApproxEqual(value(a1, i), value(a2, i), opts) {
+			return false
+		}
+	}
+	return true
+}
+
+func floatsApproxEqual(v1, v2 float64, opts EqualityOptions) bool {
+	if math.IsNaN(v1) || math.IsNaN(v2) {
+		return opts.NaNsEqual && math.IsNaN(v1) && math.IsNaN(v2)
+	}
+	if v1 == v2 {
+		// handles infinite values which can't be compared with a tolerance
+		return true
+	}
+	tolerance := opts.AbsoluteTolerance + opts.RelativeTolerance*max(math.Abs(v1), math.Abs(v2))
+	return math.Abs(v1-v2) <= tolerance
+}
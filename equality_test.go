@@ -2,10 +2,12 @@ package arrowops
 
 import (
 	"fmt"
+	"math"
 	"testing"
 
 	"github.com/apache/arrow/go/v17/arrow"
 	"github.com/apache/arrow/go/v17/arrow/array"
+	"github.com/apache/arrow/go/v17/arrow/decimal128"
 	"github.com/apache/arrow/go/v17/arrow/memory"
 )
 
@@ -75,3 +77,263 @@ func TestRecordsEqual(t *testing.T) {
 		t.Errorf("expected records to be equal")
 	}
 }
+
+func TestRecordsApproxEqual(t *testing.T) {
+
+	mem := memory.NewGoAllocator()
+
+	recordBldr := func(swapColumns bool, aValues []float64, bValues []int64) func() arrow.Record {
+		return func() arrow.Record {
+			recBuilder := array.NewRecordBuilder(mem, arrow.NewSchema(
+				[]arrow.Field{
+					{Name: "a", Type: arrow.PrimitiveTypes.Float64},
+					{Name: "b", Type: arrow.PrimitiveTypes.Int64},
+				}, nil),
+			)
+			defer recBuilder.Release()
+
+			recBuilder.Field(0).(*array.Float64Builder).AppendValues(aValues, nil)
+			recBuilder.Field(1).(*array.Int64Builder).AppendValues(bValues, nil)
+			record := recBuilder.NewRecord()
+			if !swapColumns {
+				return record
+			}
+			defer record.Release()
+			swapped, err := TakeRecordColumns(record, []string{"b", "a"})
+			if err != nil {
+				t.Fatalf("received unexpected error: %s", err)
+			}
+			return swapped
+		}
+	}
+
+	testCases := []struct {
+		caseName      string
+		record1       func() arrow.Record
+		record2       func() arrow.Record
+		opts          EqualityOptions
+		fields        []string
+		expectedEqual bool
+	}{
+		{
+			caseName:      "exact_match",
+			record1:       recordBldr(false, []float64{1.0, 2.0}, []int64{1, 2}),
+			record2:       recordBldr(false, []float64{1.0, 2.0}, []int64{1, 2}),
+			expectedEqual: true,
+		},
+		{
+			caseName:      "within_absolute_tolerance",
+			record1:       recordBldr(false, []float64{1.0, 2.0}, []int64{1, 2}),
+			record2:       recordBldr(false, []float64{1.0 + 1e-9, 2.0 - 1e-9}, []int64{1, 2}),
+			opts:          EqualityOptions{AbsoluteTolerance: 1e-6},
+			expectedEqual: true,
+		},
+		{
+			caseName:      "outside_absolute_tolerance",
+			record1:       recordBldr(false, []float64{1.0, 2.0}, []int64{1, 2}),
+			record2:       recordBldr(false, []float64{1.0 + 1e-3, 2.0}, []int64{1, 2}),
+			opts:          EqualityOptions{AbsoluteTolerance: 1e-6},
+			expectedEqual: false,
+		},
+		{
+			caseName:      "within_relative_tolerance",
+			record1:       recordBldr(false, []float64{1e9, 2.0}, []int64{1, 2}),
+			record2:       recordBldr(false, []float64{1e9 + 1, 2.0}, []int64{1, 2}),
+			opts:          EqualityOptions{RelativeTolerance: 1e-6},
+			expectedEqual: true,
+		},
+		{
+			caseName:      "integers_are_exact",
+			record1:       recordBldr(false, []float64{1.0, 2.0}, []int64{1, 2}),
+			record2:       recordBldr(false, []float64{1.0, 2.0}, []int64{1, 3}),
+			opts:          EqualityOptions{AbsoluteTolerance: 10},
+			expectedEqual: false,
+		},
+		{
+			caseName:      "nans_not_equal",
+			record1:       recordBldr(false, []float64{math.NaN(), 2.0}, []int64{1, 2}),
+			record2:       recordBldr(false, []float64{math.NaN(), 2.0}, []int64{1, 2}),
+			expectedEqual: false,
+		},
+		{
+			caseName:      "nans_equal",
+			record1:       recordBldr(false, []float64{math.NaN(), math.Inf(1)}, []int64{1, 2}),
+			record2:       recordBldr(false, []float64{math.NaN(), math.Inf(1)}, []int64{1, 2}),
+			opts:          EqualityOptions{NaNsEqual: true},
+			expectedEqual: true,
+		},
+		{
+			caseName:      "column_order_matters",
+			record1:       recordBldr(false, []float64{1.0, 2.0}, []int64{1, 2}),
+			record2:       recordBldr(true, []float64{1.0, 2.0}, []int64{1, 2}),
+			expectedEqual: false,
+		},
+		{
+			caseName:      "ignore_column_order",
+			record1:       recordBldr(false, []float64{1.0, 2.0}, []int64{1, 2}),
+			record2:       recordBldr(true, []float64{1.0, 2.0}, []int64{1, 2}),
+			opts:          EqualityOptions{IgnoreColumnOrder: true},
+			expectedEqual: true,
+		},
+		{
+			caseName:      "subset_of_fields",
+			record1:       recordBldr(false, []float64{1.0, 2.0}, []int64{1, 2}),
+			record2:       recordBldr(false, []float64{1.0, 2.0}, []int64{5, 6}),
+			fields:        []string{"a"},
+			expectedEqual: true,
+		},
+	}
+
+	for idx, tc := range testCases {
+		t.Run(fmt.Sprintf("case_%d:%s", idx, tc.caseName), func(t *testing.T) {
+			record1 := tc.record1()
+			record2 := tc.record2()
+			defer record1.Release()
+			defer record2.Release()
+
+			if equal := RecordsApproxEqual(record1, record2, tc.opts, tc.fields...); equal != tc.expectedEqual {
+				t.Errorf("expected %t, got %t", tc.expectedEqual, equal)
+			}
+		})
+	}
+
+}
+
+func TestArraysApproxEqualDecimal(t *testing.T) {
+	mem := memory.NewGoAllocator()
+
+	dataType := &arrow.Decimal128Type{Precision: 10, Scale: 2}
+	b1 := array.NewDecimal128Builder(mem, dataType)
+	defer b1.Release()
+	b1.AppendValues([]decimal128.Num{decimal128.FromI64(100), decimal128.FromI64(250)}, nil)
+	a1 := b1.NewDecimal128Array()
+	defer a1.Release()
+
+	b2 := array.NewDecimal128Builder(mem, dataType)
+	defer b2.Release()
+	b2.AppendValues([]decimal128.Num{decimal128.FromI64(101), decimal128.FromI64(250)}, nil)
+	a2 := b2.NewDecimal128Array()
+	defer a2.Release()
+
+	if ArraysApproxEqual(a1, a2, EqualityOptions{AbsoluteTolerance: 0.001}) {
+		t.Errorf("expected 1.00 and 1.01 to not be equal with a tolerance of 0.001")
+	}
+	if !ArraysApproxEqual(a1, a2, EqualityOptions{AbsoluteTolerance: 0.02}) {
+		t.Errorf("expected 1.00 and 1.01 to be equal with a tolerance of 0.02")
+	}
+}
+
+func BenchmarkRecordsEqualUnordered(b *testing.B) {
+	for _, size := range TEST_SIZES {
+		b.Run(fmt.Sprintf("size=%d", size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				mem := memory.NewGoAllocator()
+				b.StopTimer()
+				r1 := MockData(mem, size, "ascending")
+				defer r1.Release()
+				r2 := MockData(mem, size, "descending")
+				defer r2.Release()
+				b.StartTimer()
+
+				equal, err := RecordsEqualUnordered(r1, r2)
+				if err != nil {
+					b.Fatalf("received unexpected error: %s", err)
+				}
+				if !equal {
+					b.Fatalf("expected records to be equal")
+				}
+			}
+		})
+	}
+}
+
+func TestRecordsEqualUnordered(t *testing.T) {
+
+	mem := memory.NewGoAllocator()
+
+	recordBldr := func(aValues []int64, aValid []bool, bValues []string) func() arrow.Record {
+		return func() arrow.Record {
+			recBuilder := array.NewRecordBuilder(mem, arrow.NewSchema(
+				[]arrow.Field{
+					{Name: "a", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+					{Name: "b", Type: arrow.BinaryTypes.String},
+				}, nil),
+			)
+			defer recBuilder.Release()
+
+			recBuilder.Field(0).(*array.Int64Builder).AppendValues(aValues, aValid)
+			recBuilder.Field(1).(*array.StringBuilder).AppendValues(bValues, nil)
+			return recBuilder.NewRecord()
+		}
+	}
+
+	testCases := []struct {
+		caseName      string
+		record1       func() arrow.Record
+		record2       func() arrow.Record
+		fields        []string
+		expectedEqual bool
+	}{
+		{
+			caseName:      "same_order",
+			record1:       recordBldr([]int64{1, 2, 3}, nil, []string{"a", "b", "c"}),
+			record2:       recordBldr([]int64{1, 2, 3}, nil, []string{"a", "b", "c"}),
+			expectedEqual: true,
+		},
+		{
+			caseName:      "different_order",
+			record1:       recordBldr([]int64{1, 2, 3}, nil, []string{"a", "b", "c"}),
+			record2:       recordBldr([]int64{3, 1, 2}, nil, []string{"c", "a", "b"}),
+			expectedEqual: true,
+		},
+		{
+			caseName:      "different_multiplicities",
+			record1:       recordBldr([]int64{1, 1, 2}, nil, []string{"a", "a", "b"}),
+			record2:       recordBldr([]int64{1, 2, 2}, nil, []string{"a", "b", "b"}),
+			expectedEqual: false,
+		},
+		{
+			caseName:      "null_values",
+			record1:       recordBldr([]int64{0, 1, 0}, []bool{false, true, false}, []string{"a", "b", "c"}),
+			record2:       recordBldr([]int64{0, 0, 1}, []bool{false, false, true}, []string{"c", "a", "b"}),
+			expectedEqual: true,
+		},
+		{
+			caseName:      "null_not_equal_to_zero",
+			record1:       recordBldr([]int64{0, 1}, []bool{false, true}, []string{"a", "b"}),
+			record2:       recordBldr([]int64{0, 1}, nil, []string{"a", "b"}),
+			expectedEqual: false,
+		},
+		{
+			caseName:      "subset_of_fields",
+			record1:       recordBldr([]int64{1, 2, 3}, nil, []string{"a", "b", "c"}),
+			record2:       recordBldr([]int64{2, 3, 1}, nil, []string{"x", "y", "z"}),
+			fields:        []string{"a"},
+			expectedEqual: true,
+		},
+		{
+			caseName:      "different_number_of_rows",
+			record1:       recordBldr([]int64{1, 2, 3}, nil, []string{"a", "b", "c"}),
+			record2:       recordBldr([]int64{1, 2}, nil, []string{"a", "b"}),
+			expectedEqual: false,
+		},
+	}
+
+	for idx, tc := range testCases {
+		t.Run(fmt.Sprintf("case_%d:%s", idx, tc.caseName), func(t *testing.T) {
+			record1 := tc.record1()
+			record2 := tc.record2()
+			defer record1.Release()
+			defer record2.Release()
+
+			equal, err := RecordsEqualUnordered(record1, record2, tc.fields...)
+			if err != nil {
+				t.Fatalf("received unexpected error: %s", err)
+			}
+			if equal != tc.expectedEqual {
+				t.Errorf("expected %t, got %t", tc.expectedEqual, equal)
+			}
+		})
+	}
+
+}
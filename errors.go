@@ -19,6 +19,13 @@ var (
 	ErrNullValuesNotAllowed = errors.New("null values not allowed")
 	ErrColumnNamesRequired  = errors.New("column names required")
 	ErrNoColumnsProvided    = errors.New("no columns provided")
+	ErrLengthsNotEqual      = errors.New("lengths not equal")
+	ErrNotSorted            = errors.New("not sorted")
+	ErrLossyCast            = errors.New("lossy cast")
+	ErrOverflow             = errors.New("overflow")
+	ErrDivideByZero         = errors.New("divide by zero")
+	ErrInvalidPartitions    = errors.New("invalid number of partitions")
+	ErrDuplicateColumnName  = errors.New("duplicate column name")
 )
 
 func FErrSchemasNotEqual(record1, record2 arrow.Record, fields ...string) error {
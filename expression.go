@@ -0,0 +1,745 @@
+package arrowops
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alekLukanen/errs"
+	"github.com/apache/arrow/go/v17/arrow"
+	"github.com/apache/arrow/go/v17/arrow/array"
+	"github.com/apache/arrow/go/v17/arrow/float16"
+	"github.com/apache/arrow/go/v17/arrow/memory"
+	"github.com/apache/arrow/go/v17/arrow/scalar"
+)
+
+/*
+An expression evaluated against the rows of a record. Expressions are built
+from column references, literals, comparisons, boolean logic, IS NULL, IN and
+kernels applied with ExprApply, for example:
+
+	ExprAnd(
+		ExprEqual(ExprColumn("status"), ExprLiteral("active")),
+		ExprGreater(ExprColumn("amount"), ExprLiteral(100)),
+		ExprIsNull(ExprColumn("deleted_at")),
+	)
+
+Comparisons promote compatible data types in the same way as
+CompareRecordRowsWithOptions with TypeMismatchPromote. Null values follow SQL
+semantics, a comparison with a null value is null and AND, OR and NOT use
+three-valued logic.
+*/
+type Expression interface {
+	String() string
+	evaluate(mem *memory.GoAllocator, record arrow.Record) (exprValue, error)
+}
+
+/*
+The result of evaluating an expression. A scalar value is stored as an array
+with a single value which applies to every row of the record.
+*/
+type exprValue struct {
+	arr    arrow.Array
+	scalar bool
+}
+
+func (v exprValue) index(row int) int {
+	if v.scalar {
+		return 0
+	}
+	return row
+}
+
+/*
+Null arrays have no validity bitmap so IsNull can't be used to check them.
+*/
+func (v exprValue) isNull(row int) bool {
+	return v.arr.DataType().ID() == arrow.NULL || v.arr.IsNull(v.index(row))
+}
+
+func (v exprValue) release() {
+	v.arr.Release()
+}
+
+/*
+The number of values produced by an expression with the inputs provided,
+a single value when every input is a scalar and one value per row otherwise.
+*/
+func exprResultLength(record arrow.Record, inputs ...exprValue) (int, bool) {
+	for _, input := range inputs {
+		if !input.scalar {
+			return int(record.NumRows()), false
+		}
+	}
+	return 1, true
+}
+
+/*
+Evaluates the expression against the record and returns an array with one value
+for each row in the record.
+*/
+func EvaluateExpression(mem *memory.GoAllocator, record arrow.Record, expr Expression) (arrow.Array, error) {
+	record.Retain()
+	defer record.Release()
+
+	value, err := expr.evaluate(mem, record)
+	if err != nil {
+		return nil, errs.Wrap(err, fmt.Errorf("failed to evaluate expression %s", expr))
+	}
+	if !value.scalar {
+		return value.arr, nil
+	}
+	defer value.release()
+	return broadcastValue(mem, value.arr, int(record.NumRows()))
+}
+
+/*
+Repeats the single value of a scalar to create an array with length values.
+*/
+func broadcastValue(mem *memory.GoAllocator, arr arrow.Array, length int) (arrow.Array, error) {
+	if arr.DataType().ID() == arrow.NULL {
+		return array.MakeArrayOfNull(mem, arrow.Null, length), nil
+	}
+	indices := ZeroUint32Array(mem, length)
+	defer indices.Release()
+	return TakeArray(mem, arr, indices)
+}
+
+/*
+Evaluates the predicate against the record and returns a boolean mask with one
+value for each row in the record. Returns ErrDataTypesNotEqual if the predicate
+does not evaluate to a BOOL value.
+*/
+func EvaluatePredicate(mem *memory.GoAllocator, record arrow.Record, predicate Expression) (*array.Boolean, error) {
+	result, err := EvaluateExpression(mem, record, predicate)
+	if err != nil {
+		return nil, err
+	}
+	mask, ok := result.(*array.Boolean)
+	if !ok {
+		result.Release()
+		return nil, errs.NewStackError(fmt.Errorf("%w| predicate %s evaluated to %s instead of bool", ErrDataTypesNotEqual, predicate, result.DataType()))
+	}
+	return mask, nil
+}
+
+/*
+Returns the rows of the record where the predicate is true. Rows where the
+predicate is false or null are removed, like a SQL WHERE clause.
+*/
+func FilterRecordByExpression(mem *memory.GoAllocator, record arrow.Record, predicate Expression) (arrow.Record, error) {
+	mask, err := EvaluatePredicate(mem, record, predicate)
+	if err != nil {
+		return nil, err
+	}
+	defer mask.Release()
+	return FilterRecord(mem, record, mask, NullSelectionDrop)
+}
+
+type columnExpression struct {
+	name string
+}
+
+/*
+References the column with the name provided.
+*/
+func ExprColumn(name string) Expression {
+	return &columnExpression{name: name}
+}
+
+func (e *columnExpression) String() string {
+	return e.name
+}
+
+func (e *columnExpression) evaluate(mem *memory.GoAllocator, record arrow.Record) (exprValue, error) {
+	columnIdxs := record.Schema().FieldIndices(e.name)
+	if len(columnIdxs) == 0 {
+		return exprValue{}, errs.NewStackError(fmt.Errorf("%w| column name: %s", ErrColumnNotFound, e.name))
+	}
+	column := record.Column(columnIdxs[0])
+	column.Retain()
+	return exprValue{arr: column}, nil
+}
+
+type literalExpression struct {
+	value  any
+	scalar scalar.Scalar
+}
+
+/*
+A constant value. Supported values are nil, bool, signed and unsigned integers,
+float16.Num, float32, float64, string, []byte, arrow.Date32, arrow.Date64,
+time.Time, which becomes a nanosecond UTC timestamp, and scalar.Scalar.
+*/
+func ExprLiteral(value any) Expression {
+	return &literalExpression{value: value, scalar: literalScalar(value)}
+}
+
+func literalScalar(value any) scalar.Scalar {
+	switch v := value.(type) {
+	case scalar.Scalar:
+		return v
+	case time.Time:
+		return scalar.NewTimestampScalar(arrow.Timestamp(v.UnixNano()), arrow.FixedWidthTypes.Timestamp_ns)
+	case nil, bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64,
+		float16.Num, float32, float64, string, []byte, arrow.Date32, arrow.Date64:
+		return scalar.MakeScalar(v)
+	default:
+		return nil
+	}
+}
+
+func (e *literalExpression) String() string {
+	switch v := e.value.(type) {
+	case nil:
+		return "NULL"
+	case string:
+		return strconv.Quote(v)
+	case scalar.Scalar:
+		return v.String()
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func (e *literalExpression) evaluate(mem *memory.GoAllocator, record arrow.Record) (exprValue, error) {
+	if e.scalar == nil {
+		return exprValue{}, errs.NewStackError(fmt.Errorf("%w| literal value %v of type %T", ErrUnsupportedDataType, e.value, e.value))
+	}
+	arr, err := scalar.MakeArrayFromScalar(e.scalar, 1, mem)
+	if err != nil {
+		return exprValue{}, errs.Wrap(err, fmt.Errorf("failed to create array for literal %s", e))
+	}
+	return exprValue{arr: arr, scalar: true}, nil
+}
+
+type comparisonOperator int
+
+const (
+	equalOperator comparisonOperator = iota
+	notEqualOperator
+	lessOperator
+	lessEqualOperator
+	greaterOperator
+	greaterEqualOperator
+)
+
+func (op comparisonOperator) String() string {
+	switch op {
+	case equalOperator:
+		return "=="
+	case notEqualOperator:
+		return "!="
+	case lessOperator:
+		return "<"
+	case lessEqualOperator:
+		return "<="
+	case greaterOperator:
+		return ">"
+	default:
+		return ">="
+	}
+}
+
+/*
+Applies the operator to the result of comparing two values
+where less than is -1, equal to is 0 and greater than is 1.
+*/
+func (op comparisonOperator) test(compareValue int) bool {
+	switch op {
+	case equalOperator:
+		return compareValue == 0
+	case notEqualOperator:
+		return compareValue != 0
+	case lessOperator:
+		return compareValue < 0
+	case lessEqualOperator:
+		return compareValue <= 0
+	case greaterOperator:
+		return compareValue > 0
+	default:
+		return compareValue >= 0
+	}
+}
+
+type comparisonExpression struct {
+	op          comparisonOperator
+	left, right Expression
+}
+
+func ExprEqual(left, right Expression) Expression {
+	return &comparisonExpression{op: equalOperator, left: left, right: right}
+}
+
+func ExprNotEqual(left, right Expression) Expression {
+	return &comparisonExpression{op: notEqualOperator, left: left, right: right}
+}
+
+func ExprLess(left, right Expression) Expression {
+	return &comparisonExpression{op: lessOperator, left: left, right: right}
+}
+
+func ExprLessEqual(left, right Expression) Expression {
+	return &comparisonExpression{op: lessEqualOperator, left: left, right: right}
+}
+
+func ExprGreater(left, right Expression) Expression {
+	return &comparisonExpression{op: greaterOperator, left: left, right: right}
+}
+
+func ExprGreaterEqual(left, right Expression) Expression {
+	return &comparisonExpression{op: greaterEqualOperator, left: left, right: right}
+}
+
+func (e *comparisonExpression) String() string {
+	return fmt.Sprintf("(%s %s %s)", e.left, e.op, e.right)
+}
+
+func (e *comparisonExpression) evaluate(mem *memory.GoAllocator, record arrow.Record) (exprValue, error) {
+	left, err := e.left.evaluate(mem, record)
+	if err != nil {
+		return exprValue{}, err
+	}
+	defer left.release()
+	right, err := e.right.evaluate(mem, record)
+	if err != nil {
+		return exprValue{}, err
+	}
+	defer right.release()
+
+	length, isScalar := exprResultLength(record, left, right)
+	b := array.NewBooleanBuilder(mem)
+	defer b.Release()
+	b.Reserve(length)
+	for i := 0; i < length; i++ {
+		leftIdx, rightIdx := left.index(i), right.index(i)
+		if left.isNull(i) || right.isNull(i) {
+			b.AppendNull()
+			continue
+		}
+		compareValue, err := comparePromotedArrayValues(left.arr, right.arr, leftIdx, rightIdx)
+		if err != nil {
+			return exprValue{}, errs.Wrap(err, fmt.Errorf("failed to evaluate %s", e))
+		}
+		b.Append(e.op.test(compareValue))
+	}
+	return exprValue{arr: b.NewBooleanArray(), scalar: isScalar}, nil
+}
+
+type logicalExpression struct {
+	and      bool
+	operands []Expression
+}
+
+/*
+True when every operand is true, false when any operand is false
+and null otherwise.
+*/
+func ExprAnd(operands ...Expression) Expression {
+	return &logicalExpression{and: true, operands: operands}
+}
+
+/*
+True when any operand is true, false when every operand is false
+and null otherwise.
+*/
+func ExprOr(operands ...Expression) Expression {
+	return &logicalExpression{and: false, operands: operands}
+}
+
+func (e *logicalExpression) String() string {
+	operator := " OR "
+	if e.and {
+		operator = " AND "
+	}
+	operands := make([]string, len(e.operands))
+	for i, operand := range e.operands {
+		operands[i] = operand.String()
+	}
+	return "(" + strings.Join(operands, operator) + ")"
+}
+
+func (e *logicalExpression) evaluate(mem *memory.GoAllocator, record arrow.Record) (exprValue, error) {
+	if len(e.operands) == 0 {
+		return exprValue{}, errs.NewStackError(fmt.Errorf("%w| %s requires at least one operand", ErrNoDataSupplied, e))
+	}
+
+	operands := make([]exprValue, 0, len(e.operands))
+	defer func() {
+		for _, operand := range operands {
+			operand.release()
+		}
+	}()
+	for _, operandExpr := range e.operands {
+		operand, err := evaluateBooleanExpression(mem, record, operandExpr)
+		if err != nil {
+			return exprValue{}, err
+		}
+		operands = append(operands, operand)
+	}
+
+	length, isScalar := exprResultLength(record, operands...)
+	b := array.NewBooleanBuilder(mem)
+	defer b.Release()
+	b.Reserve(length)
+	for i := 0; i < length; i++ {
+		// the value that decides the result when any operand has it
+		decided := false
+		hasNull := false
+		for _, operand := range operands {
+			idx := operand.index(i)
+			if operand.isNull(i) {
+				hasNull = true
+			} else if operand.arr.(*array.Boolean).Value(idx) != e.and {
+				decided = true
+				break
+			}
+		}
+		if decided {
+			b.Append(!e.and)
+		} else if hasNull {
+			b.AppendNull()
+		} else {
+			b.Append(e.and)
+		}
+	}
+	return exprValue{arr: b.NewBooleanArray(), scalar: isScalar}, nil
+}
+
+type notExpression struct {
+	operand Expression
+}
+
+/*
+True when the operand is false, false when the operand is true and null otherwise.
+*/
+func ExprNot(operand Expression) Expression {
+	return &notExpression{operand: operand}
+}
+
+func (e *notExpression) String() string {
+	return fmt.Sprintf("(NOT %s)", e.operand)
+}
+
+func (e *notExpression) evaluate(mem *memory.GoAllocator, record arrow.Record) (exprValue, error) {
+	operand, err := evaluateBooleanExpression(mem, record, e.operand)
+	if err != nil {
+		return exprValue{}, err
+	}
+	defer operand.release()
+
+	values := operand.arr.(*array.Boolean)
+	b := array.NewBooleanBuilder(mem)
+	defer b.Release()
+	b.Reserve(values.Len())
+	for i := 0; i < values.Len(); i++ {
+		if values.IsNull(i) {
+			b.AppendNull()
+			continue
+		}
+		b.Append(!values.Value(i))
+	}
+	return exprValue{arr: b.NewBooleanArray(), scalar: operand.scalar}, nil
+}
+
+type isNullExpression struct {
+	operand Expression
+	negate  bool
+}
+
+/*
+True when the operand is null and false otherwise, never null.
+*/
+func ExprIsNull(operand Expression) Expression {
+	return &isNullExpression{operand: operand}
+}
+
+/*
+True when the operand is not null and false otherwise, never null.
+*/
+func ExprIsNotNull(operand Expression) Expression {
+	return &isNullExpression{operand: operand, negate: true}
+}
+
+func (e *isNullExpression) String() string {
+	if e.negate {
+		return fmt.Sprintf("(%s IS NOT NULL)", e.operand)
+	}
+	return fmt.Sprintf("(%s IS NULL)", e.operand)
+}
+
+func (e *isNullExpression) evaluate(mem *memory.GoAllocator, record arrow.Record) (exprValue, error) {
+	operand, err := e.operand.evaluate(mem, record)
+	if err != nil {
+		return exprValue{}, err
+	}
+	defer operand.release()
+
+	b := array.NewBooleanBuilder(mem)
+	defer b.Release()
+	b.Reserve(operand.arr.Len())
+	for i := 0; i < operand.arr.Len(); i++ {
+		b.Append(operand.isNull(i) != e.negate)
+	}
+	return exprValue{arr: b.NewBooleanArray(), scalar: operand.scalar}, nil
+}
+
+type inExpression struct {
+	operand Expression
+	values  []Expression
+}
+
+/*
+True when the operand is equal to one of the values. When no value is equal the
+result is null if the operand or any of the values is null and false otherwise.
+The values are literals created in the same way as ExprLiteral, they are cast to the
+operand's data type and tested with IsIn.
+*/
+func ExprIn(operand Expression, values ...any) Expression {
+	literals := make([]Expression, len(values))
+	for i, value := range values {
+		literals[i] = ExprLiteral(value)
+	}
+	return &inExpression{operand: operand, values: literals}
+}
+
+func (e *inExpression) String() string {
+	values := make([]string, len(e.values))
+	for i, value := range e.values {
+		values[i] = value.String()
+	}
+	return fmt.Sprintf("(%s IN (%s))", e.operand, strings.Join(values, ", "))
+}
+
+func (e *inExpression) evaluate(mem *memory.GoAllocator, record arrow.Record) (exprValue, error) {
+	operand, err := e.operand.evaluate(mem, record)
+	if err != nil {
+		return exprValue{}, err
+	}
+	defer operand.release()
+
+	values := make([]exprValue, 0, len(e.values))
+	defer func() {
+		for _, value := range values {
+			value.release()
+		}
+	}()
+	for _, valueExpr := range e.values {
+		value, err := valueExpr.evaluate(mem, record)
+		if err != nil {
+			return exprValue{}, err
+		}
+		values = append(values, value)
+	}
+
+	// when every operand value is null the values don't need to be compared
+	if operand.arr.NullN() == operand.arr.Len() {
+		mask := array.MakeArrayOfNull(mem, arrow.FixedWidthTypes.Boolean, operand.arr.Len())
+		return exprValue{arr: mask, scalar: operand.scalar}, nil
+	}
+
+	valueSet, ok, err := e.valueSet(mem, operand.arr, values)
+	if err != nil {
+		return exprValue{}, errs.Wrap(err, fmt.Errorf("failed to evaluate %s", e))
+	}
+	if !ok {
+		mask, err := e.compareValues(mem, operand, values)
+		if err != nil {
+			return exprValue{}, errs.Wrap(err, fmt.Errorf("failed to evaluate %s", e))
+		}
+		return exprValue{arr: mask, scalar: operand.scalar}, nil
+	}
+	defer valueSet.Release()
+
+	mask, err := IsIn(mem, operand.arr, valueSet)
+	if err != nil {
+		return exprValue{}, errs.Wrap(err, fmt.Errorf("failed to evaluate %s", e))
+	}
+	return exprValue{arr: mask, scalar: operand.scalar}, nil
+}
+
+/*
+Casts the values to the data type of the operand so they can be tested with IsIn.
+Values that can not be cast without losing information are never equal to an
+operand value so they are left out of the set. Returns false when a value can be
+compared with the operand but CastArray does not support its data type, for
+example a BINARY value and a LARGE_BINARY operand.
+*/
+func (e *inExpression) valueSet(mem *memory.GoAllocator, operand arrow.Array, values []exprValue) (arrow.Array, bool, error) {
+	// the first non-null operand value is used to check that
+	// the values can be compared with the operand
+	row := 0
+	for operand.IsNull(row) {
+		row++
+	}
+
+	b := array.NewBuilder(mem, operand.DataType())
+	defer b.Release()
+	valueSet := make([]arrow.Array, 0, len(values)+1)
+	defer func() {
+		releaseArrays(valueSet)
+	}()
+	for _, value := range values {
+		if value.isNull(0) {
+			b.AppendNull()
+			continue
+		}
+		if _, err := comparePromotedArrayValues(operand, value.arr, row, 0); err != nil {
+			return nil, false, err
+		}
+		castValue, err := CastArray(mem, value.arr, operand.DataType(), CastOptions{})
+		if errors.Is(err, ErrLossyCast) {
+			continue
+		} else if errors.Is(err, ErrUnsupportedDataType) {
+			return nil, false, nil
+		} else if err != nil {
+			return nil, false, err
+		}
+		valueSet = append(valueSet, castValue)
+	}
+	valueSet = append(valueSet, b.NewArray())
+	set, err := array.Concatenate(valueSet, mem)
+	if err != nil {
+		return nil, false, err
+	}
+	return set, true, nil
+}
+
+/*
+Compares each operand value with every value using comparePromotedArrayValues,
+used when the values can't be cast to the data type of the operand.
+*/
+func (e *inExpression) compareValues(mem *memory.GoAllocator, operand exprValue, values []exprValue) (*array.Boolean, error) {
+	b := array.NewBooleanBuilder(mem)
+	defer b.Release()
+	b.Reserve(operand.arr.Len())
+	for i := 0; i < operand.arr.Len(); i++ {
+		if operand.isNull(i) {
+			b.AppendNull()
+			continue
+		}
+		found := false
+		hasNull := false
+		for _, value := range values {
+			if value.isNull(0) {
+				hasNull = true
+				continue
+			}
+			compareValue, err := comparePromotedArrayValues(operand.arr, value.arr, i, 0)
+			if err != nil {
+				return nil, err
+			}
+			if compareValue == 0 {
+				found = true
+				break
+			}
+		}
+		if found {
+			b.Append(true)
+		} else if hasNull {
+			b.AppendNull()
+		} else {
+			b.Append(false)
+		}
+	}
+	return b.NewBooleanArray(), nil
+}
+
+/*
+A function computed from arrays, like the string and arithmetic kernels, that
+returns an array with one value for each value of the inputs.
+*/
+type ExprKernel func(mem *memory.GoAllocator, inputs ...arrow.Array) (arrow.Array, error)
+
+type applyExpression struct {
+	name   string
+	kernel ExprKernel
+	inputs []Expression
+}
+
+/*
+Computes the kernel from the values of the input expressions so kernels can be
+used in predicates and projections, for example:
+
+	ExprApply("lower", func(mem *memory.GoAllocator, inputs ...arrow.Array) (arrow.Array, error) {
+		return StringLower(mem, inputs[0])
+	}, ExprColumn("name"))
+
+Scalar inputs are repeated for each row of the record unless every input is a
+scalar, in which case the kernel is computed once.
+*/
+func ExprApply(name string, kernel ExprKernel, inputs ...Expression) Expression {
+	return &applyExpression{name: name, kernel: kernel, inputs: inputs}
+}
+
+func (e *applyExpression) String() string {
+	inputs := make([]string, len(e.inputs))
+	for i, input := range e.inputs {
+		inputs[i] = input.String()
+	}
+	return fmt.Sprintf("%s(%s)", e.name, strings.Join(inputs, ", "))
+}
+
+func (e *applyExpression) evaluate(mem *memory.GoAllocator, record arrow.Record) (exprValue, error) {
+	values := make([]exprValue, 0, len(e.inputs))
+	defer func() {
+		for _, value := range values {
+			value.release()
+		}
+	}()
+	for _, input := range e.inputs {
+		value, err := input.evaluate(mem, record)
+		if err != nil {
+			return exprValue{}, err
+		}
+		values = append(values, value)
+	}
+
+	length, scalar := exprResultLength(record, values...)
+	arrays := make([]arrow.Array, len(values))
+	for i, value := range values {
+		if value.scalar && !scalar {
+			arr, err := broadcastValue(mem, value.arr, length)
+			if err != nil {
+				return exprValue{}, errs.Wrap(err, fmt.Errorf("failed to expand scalar input %s", e.inputs[i]))
+			}
+			value.release()
+			values[i] = exprValue{arr: arr}
+		}
+		arrays[i] = values[i].arr
+	}
+
+	result, err := e.kernel(mem, arrays...)
+	if err != nil {
+		return exprValue{}, errs.Wrap(err, fmt.Errorf("failed to compute %s", e))
+	}
+	if resultLength := result.Len(); resultLength != length {
+		result.Release()
+		return exprValue{}, errs.NewStackError(fmt.Errorf("%w| %s returned %d values for %d rows", ErrLengthsNotEqual, e, resultLength, length))
+	}
+	return exprValue{arr: result, scalar: scalar}, nil
+}
+
+/*
+Evaluates an operand of a boolean expression and checks that it is a BOOL value.
+A null literal is converted to a null BOOL value.
+*/
+func evaluateBooleanExpression(mem *memory.GoAllocator, record arrow.Record, expr Expression) (exprValue, error) {
+	value, err := expr.evaluate(mem, record)
+	if err != nil {
+		return exprValue{}, err
+	}
+	switch value.arr.DataType().ID() {
+	case arrow.BOOL:
+		return value, nil
+	case arrow.NULL:
+		defer value.release()
+		nulls := array.MakeArrayOfNull(mem, arrow.FixedWidthTypes.Boolean, value.arr.Len())
+		return exprValue{arr: nulls, scalar: value.scalar}, nil
+	default:
+		value.release()
+		return exprValue{}, errs.NewStackError(fmt.Errorf("%w| %s evaluated to %s instead of bool", ErrDataTypesNotEqual, expr, value.arr.DataType()))
+	}
+}
@@ -0,0 +1,319 @@
+package arrowops
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/apache/arrow/go/v17/arrow"
+	"github.com/apache/arrow/go/v17/arrow/array"
+	"github.com/apache/arrow/go/v17/arrow/memory"
+)
+
+func booleanArrayValues(arr *array.Boolean) []string {
+	values := make([]string, arr.Len())
+	for i := range values {
+		if arr.IsNull(i) {
+			values[i] = "null"
+		} else {
+			values[i] = fmt.Sprint(arr.Value(i))
+		}
+	}
+	return values
+}
+
+func TestEvaluatePredicate(t *testing.T) {
+
+	mem := memory.NewGoAllocator()
+
+	record := recordFromJSON(t, mem, arrow.NewSchema(
+		[]arrow.Field{
+			{Name: "status", Type: arrow.BinaryTypes.String},
+			{Name: "amount", Type: arrow.PrimitiveTypes.Int32, Nullable: true},
+			{Name: "deleted_at", Type: arrow.FixedWidthTypes.Timestamp_s, Nullable: true},
+		}, nil), `[
+			{"status": "active", "amount": 150, "deleted_at": null},
+			{"status": "active", "amount": 50, "deleted_at": null},
+			{"status": "closed", "amount": 200, "deleted_at": null},
+			{"status": "active", "amount": null, "deleted_at": null},
+			{"status": "active", "amount": 300, "deleted_at": 10}
+		]`,
+	)
+	defer record.Release()
+
+	testCases := []struct {
+		caseName     string
+		predicate    Expression
+		expectedMask []string
+	}{
+		{
+			caseName:     "equal",
+			predicate:    ExprEqual(ExprColumn("status"), ExprLiteral("active")),
+			expectedMask: []string{"true", "true", "false", "true", "true"},
+		},
+		{
+			caseName:     "greater_with_promotion_and_nulls",
+			predicate:    ExprGreater(ExprColumn("amount"), ExprLiteral(100)),
+			expectedMask: []string{"true", "false", "true", "null", "true"},
+		},
+		{
+			caseName:     "literal_on_the_left",
+			predicate:    ExprLessEqual(ExprLiteral(int64(150)), ExprColumn("amount")),
+			expectedMask: []string{"true", "false", "true", "null", "true"},
+		},
+		{
+			caseName:     "is_null",
+			predicate:    ExprIsNull(ExprColumn("deleted_at")),
+			expectedMask: []string{"true", "true", "true", "true", "false"},
+		},
+		{
+			caseName:     "is_not_null",
+			predicate:    ExprIsNotNull(ExprColumn("amount")),
+			expectedMask: []string{"true", "true", "true", "false", "true"},
+		},
+		{
+			caseName: "and",
+			predicate: ExprAnd(
+				ExprEqual(ExprColumn("status"), ExprLiteral("active")),
+				ExprGreater(ExprColumn("amount"), ExprLiteral(100)),
+				ExprIsNull(ExprColumn("deleted_at")),
+			),
+			expectedMask: []string{"true", "false", "false", "null", "false"},
+		},
+		{
+			caseName: "or",
+			predicate: ExprOr(
+				ExprEqual(ExprColumn("status"), ExprLiteral("closed")),
+				ExprGreater(ExprColumn("amount"), ExprLiteral(100)),
+			),
+			expectedMask: []string{"true", "false", "true", "null", "true"},
+		},
+		{
+			caseName:     "not",
+			predicate:    ExprNot(ExprGreater(ExprColumn("amount"), ExprLiteral(100))),
+			expectedMask: []string{"false", "true", "false", "null", "false"},
+		},
+		{
+			caseName:     "in",
+			predicate:    ExprIn(ExprColumn("amount"), 50, 200),
+			expectedMask: []string{"false", "true", "true", "null", "false"},
+		},
+		{
+			caseName:     "in_with_null_value",
+			predicate:    ExprIn(ExprColumn("amount"), 50, nil),
+			expectedMask: []string{"null", "true", "null", "null", "null"},
+		},
+		{
+			caseName:     "in_with_values_outside_the_column_type",
+			predicate:    ExprIn(ExprColumn("amount"), 2.5, int64(1)<<40, 200.0),
+			expectedMask: []string{"false", "false", "true", "null", "false"},
+		},
+		{
+			caseName:     "scalar_predicate",
+			predicate:    ExprLess(ExprLiteral(1), ExprLiteral(2.5)),
+			expectedMask: []string{"true", "true", "true", "true", "true"},
+		},
+	}
+
+	for idx, tc := range testCases {
+		t.Run(fmt.Sprintf("case_%d:%s", idx, tc.caseName), func(t *testing.T) {
+			mask, err := EvaluatePredicate(mem, record, tc.predicate)
+			if err != nil {
+				t.Fatalf("received unexpected error: %s", err)
+			}
+			defer mask.Release()
+
+			if actual := booleanArrayValues(mask); !slices.Equal(actual, tc.expectedMask) {
+				t.Errorf("expected mask %v for %s, got %v", tc.expectedMask, tc.predicate, actual)
+			}
+		})
+	}
+
+}
+
+func TestFilterRecordByExpression(t *testing.T) {
+	mem := memory.NewGoAllocator()
+
+	record := recordFromJSON(t, mem, arrow.NewSchema(
+		[]arrow.Field{
+			{Name: "status", Type: arrow.BinaryTypes.String},
+			{Name: "amount", Type: arrow.PrimitiveTypes.Int32, Nullable: true},
+			{Name: "deleted_at", Type: arrow.FixedWidthTypes.Timestamp_s, Nullable: true},
+		}, nil), `[
+			{"status": "active", "amount": 150, "deleted_at": null},
+			{"status": "active", "amount": 50, "deleted_at": null},
+			{"status": "closed", "amount": 200, "deleted_at": null},
+			{"status": "active", "amount": null, "deleted_at": null},
+			{"status": "active", "amount": 300, "deleted_at": 10}
+		]`,
+	)
+	defer record.Release()
+
+	predicate := ExprAnd(
+		ExprEqual(ExprColumn("status"), ExprLiteral("active")),
+		ExprGreater(ExprColumn("amount"), ExprLiteral(100)),
+		ExprIsNull(ExprColumn("deleted_at")),
+	)
+	if predicate.String() != `((status == "active") AND (amount > 100) AND (deleted_at IS NULL))` {
+		t.Errorf("unexpected predicate string %s", predicate)
+	}
+
+	filtered, err := FilterRecordByExpression(mem, record, predicate)
+	if err != nil {
+		t.Fatalf("received unexpected error: %s", err)
+	}
+	defer filtered.Release()
+
+	if filtered.NumRows() != 1 || filtered.Column(1).(*array.Int32).Value(0) != 150 {
+		t.Errorf("expected only the row with amount 150, got %v", filtered)
+	}
+}
+
+func TestEvaluatePredicateErrors(t *testing.T) {
+	mem := memory.NewGoAllocator()
+
+	record := recordFromJSON(t, mem, arrow.NewSchema(
+		[]arrow.Field{
+			{Name: "status", Type: arrow.BinaryTypes.String},
+			{Name: "amount", Type: arrow.PrimitiveTypes.Int32, Nullable: true},
+		}, nil),
+		`[{"status": "active", "amount": 150}]`,
+	)
+	defer record.Release()
+
+	testCases := []struct {
+		caseName    string
+		predicate   Expression
+		expectedErr error
+	}{
+		{
+			caseName:    "column_not_found",
+			predicate:   ExprEqual(ExprColumn("z"), ExprLiteral(1)),
+			expectedErr: ErrColumnNotFound,
+		},
+		{
+			caseName:    "incompatible_types",
+			predicate:   ExprEqual(ExprColumn("status"), ExprLiteral(1)),
+			expectedErr: ErrDataTypesNotEqual,
+		},
+		{
+			caseName:    "incompatible_in_values",
+			predicate:   ExprIn(ExprColumn("status"), "active", 1),
+			expectedErr: ErrDataTypesNotEqual,
+		},
+		{
+			caseName:    "not_a_predicate",
+			predicate:   ExprColumn("amount"),
+			expectedErr: ErrDataTypesNotEqual,
+		},
+		{
+			caseName:    "non_boolean_operand",
+			predicate:   ExprAnd(ExprColumn("amount"), ExprIsNull(ExprColumn("amount"))),
+			expectedErr: ErrDataTypesNotEqual,
+		},
+		{
+			caseName:    "unsupported_literal",
+			predicate:   ExprEqual(ExprColumn("amount"), ExprLiteral(struct{}{})),
+			expectedErr: ErrUnsupportedDataType,
+		},
+	}
+
+	for idx, tc := range testCases {
+		t.Run(fmt.Sprintf("case_%d:%s", idx, tc.caseName), func(t *testing.T) {
+			_, err := EvaluatePredicate(mem, record, tc.predicate)
+			if !errors.Is(err, tc.expectedErr) {
+				t.Errorf("expected error %v, got %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestApplyExpression(t *testing.T) {
+	mem := memory.NewGoAllocator()
+
+	record := recordFromJSON(t, mem, arrow.NewSchema(
+		[]arrow.Field{
+			{Name: "status", Type: arrow.BinaryTypes.String},
+			{Name: "amount", Type: arrow.PrimitiveTypes.Int32, Nullable: true},
+			{Name: "deleted_at", Type: arrow.FixedWidthTypes.Timestamp_s, Nullable: true},
+		}, nil), `[
+			{"status": "active", "amount": 150, "deleted_at": null},
+			{"status": "active", "amount": 50, "deleted_at": null},
+			{"status": "closed", "amount": 200, "deleted_at": null},
+			{"status": "active", "amount": null, "deleted_at": null},
+			{"status": "active", "amount": 300, "deleted_at": 10}
+		]`,
+	)
+	defer record.Release()
+
+	startsWith := func(mem *memory.GoAllocator, inputs ...arrow.Array) (arrow.Array, error) {
+		return StringStartsWith(mem, inputs[0], "act")
+	}
+	concat := func(mem *memory.GoAllocator, inputs ...arrow.Array) (arrow.Array, error) {
+		return StringConcat(mem, "-", inputs...)
+	}
+
+	predicate := ExprAnd(ExprApply("starts_with", startsWith, ExprColumn("status")), ExprGreater(ExprColumn("amount"), ExprLiteral(100)))
+	mask, err := EvaluatePredicate(mem, record, predicate)
+	if err != nil {
+		t.Fatalf("received unexpected error: %s", err)
+	}
+	defer mask.Release()
+	expectedMask := []string{"true", "false", "false", "null", "true"}
+	if actual := booleanArrayValues(mask); !slices.Equal(actual, expectedMask) {
+		t.Errorf("expected mask %v, got %v", expectedMask, actual)
+	}
+
+	// the scalar input is repeated for every row
+	projection := ExprApply("concat", concat, ExprColumn("status"), ExprLiteral("x"))
+	if projection.String() != `concat(status, "x")` {
+		t.Errorf("expected string %s, got %s", `concat(status, "x")`, projection.String())
+	}
+	result, err := EvaluateExpression(mem, record, projection)
+	if err != nil {
+		t.Fatalf("received unexpected error: %s", err)
+	}
+	defer result.Release()
+	expectedValues := []string{"active-x", "active-x", "closed-x", "active-x", "active-x"}
+	if actual := arrayValueStrings(result); !slices.Equal(actual, expectedValues) {
+		t.Errorf("expected values %v, got %v", expectedValues, actual)
+	}
+
+	// a kernel must return one value for each row
+	first := func(mem *memory.GoAllocator, inputs ...arrow.Array) (arrow.Array, error) {
+		return array.NewSlice(inputs[0], 0, 1), nil
+	}
+	_, err = EvaluateExpression(mem, record, ExprApply("first", first, ExprColumn("status")))
+	if !errors.Is(err, ErrLengthsNotEqual) {
+		t.Errorf("expected error %v, got %v", ErrLengthsNotEqual, err)
+	}
+}
+
+func TestInWithValuesThatCanNotBeCast(t *testing.T) {
+	mem := memory.NewGoAllocator()
+
+	recBuilder := array.NewRecordBuilder(mem, arrow.NewSchema(
+		[]arrow.Field{{Name: "k", Type: arrow.BinaryTypes.LargeBinary, Nullable: true}}, nil),
+	)
+	defer recBuilder.Release()
+	recBuilder.Field(0).(*array.BinaryBuilder).AppendValues([][]byte{[]byte("x"), []byte("y"), nil}, []bool{true, true, false})
+	record := recBuilder.NewRecord()
+	defer record.Release()
+
+	// BINARY literals can be compared with LARGE_BINARY values but not cast to them
+	for _, predicate := range []Expression{
+		ExprIn(ExprColumn("k"), []byte("x")),
+		ExprEqual(ExprColumn("k"), ExprLiteral([]byte("x"))),
+	} {
+		mask, err := EvaluatePredicate(mem, record, predicate)
+		if err != nil {
+			t.Fatalf("received unexpected error for %s: %s", predicate, err)
+		}
+		defer mask.Release()
+		expectedMask := []string{"true", "false", "null"}
+		if actual := booleanArrayValues(mask); !slices.Equal(actual, expectedMask) {
+			t.Errorf("expected mask %v for %s, got %v", expectedMask, predicate, actual)
+		}
+	}
+}
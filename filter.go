@@ -0,0 +1,200 @@
+package arrowops
+
+import (
+	"fmt"
+
+	"github.com/alekLukanen/errs"
+	"github.com/apache/arrow/go/v17/arrow"
+	"github.com/apache/arrow/go/v17/arrow/array"
+	"github.com/apache/arrow/go/v17/arrow/bitutil"
+	"github.com/apache/arrow/go/v17/arrow/memory"
+)
+
+/*
+How null values in a filter mask are handled. NullSelectionDrop removes the row
+from the result, NullSelectionEmitNull keeps a null value in its place.
+*/
+type NullSelection int
+
+const (
+	NullSelectionDrop NullSelection = iota
+	NullSelectionEmitNull
+)
+
+// selections with runs at least this long on average are
+// copied by concatenating slices instead of taking each row
+const filterMinAverageRunLength = 16
+
+/*
+Filter the rows of the record using the mask. Rows where the mask is true are
+kept, rows where the mask is false are removed and rows where the mask is null
+are handled based on the null selection. When every row is kept the record is
+returned without copying, and when the kept rows are contiguous the result is
+a slice of the record.
+*/
+func FilterRecord(mem *memory.GoAllocator, record arrow.Record, mask *array.Boolean, nullSelection NullSelection) (arrow.Record, error) {
+	record.Retain()
+	defer record.Release()
+
+	if int64(mask.Len()) != record.NumRows() {
+		return nil, errs.NewStackError(fmt.Errorf(
+			"%w| mask has %d values but the record has %d rows", ErrLengthsNotEqual, mask.Len(), record.NumRows(),
+		))
+	}
+
+	selection := newFilterSelection(mask, nullSelection)
+	switch {
+	case selection.numRows == 0:
+		columns := make([]arrow.Array, record.NumCols())
+		for i := range columns {
+			columns[i] = array.MakeArrayOfNull(mem, record.Column(i).DataType(), 0)
+			defer columns[i].Release()
+		}
+		return array.NewRecord(record.Schema(), columns, 0), nil
+	case len(selection.runs) == 1:
+		return record.NewSlice(int64(selection.runs[0][0]), int64(selection.runs[0][1])), nil
+	}
+
+	indices := selection.indicesArray(mem)
+	defer indices.Release()
+
+	columns := make([]arrow.Array, record.NumCols())
+	for i := range columns {
+		column, err := selection.filter(mem, record.Column(i), indices)
+		if err != nil {
+			return nil, errs.Wrap(err, fmt.Errorf("failed to filter column %s", record.ColumnName(i)))
+		}
+		columns[i] = column
+		defer column.Release()
+	}
+	return array.NewRecord(record.Schema(), columns, int64(selection.numRows)), nil
+}
+
+/*
+Filter the values of the array using the mask, in the same way as FilterRecord.
+*/
+func FilterArray(mem *memory.GoAllocator, arr arrow.Array, mask *array.Boolean, nullSelection NullSelection) (arrow.Array, error) {
+	if mask.Len() != arr.Len() {
+		return nil, errs.NewStackError(fmt.Errorf(
+			"%w| mask has %d values but the array has %d values", ErrLengthsNotEqual, mask.Len(), arr.Len(),
+		))
+	}
+
+	selection := newFilterSelection(mask, nullSelection)
+	switch {
+	case selection.numRows == 0:
+		return array.MakeArrayOfNull(mem, arr.DataType(), 0), nil
+	case len(selection.runs) == 1:
+		return array.NewSlice(arr, int64(selection.runs[0][0]), int64(selection.runs[0][1])), nil
+	}
+
+	indices := selection.indicesArray(mem)
+	defer indices.Release()
+	return selection.filter(mem, arr, indices)
+}
+
+/*
+The rows selected by a filter mask. The selected rows are stored as indices,
+with valid set to false for rows that should be null. When no row is null the
+indices are also grouped into runs of contiguous rows.
+*/
+type filterSelection struct {
+	numRows int
+	indices []uint32
+	valid   []bool
+	// half-open ranges of contiguous selected rows
+	runs [][2]int
+}
+
+func newFilterSelection(mask *array.Boolean, nullSelection NullSelection) *filterSelection {
+	numValues := mask.Len()
+	emitNulls := nullSelection == NullSelectionEmitNull && mask.NullN() > 0
+
+	selection := &filterSelection{}
+	if numValues == 0 {
+		// empty masks may not have a values buffer
+		return selection
+	}
+	if mask.NullN() == 0 {
+		values := mask.Data().Buffers()[1].Bytes()
+		offset := mask.Data().Offset()
+		selection.numRows = bitutil.CountSetBits(values, offset, numValues)
+		selection.indices = make([]uint32, 0, selection.numRows)
+		for i := 0; i < numValues; {
+			// skip whole bytes of false values so selective masks are fast
+			bit := offset + i
+			if bit%8 == 0 && i+8 <= numValues && values[bit/8] == 0 {
+				i += 8
+				continue
+			}
+			if bitutil.BitIsSet(values, bit) {
+				selection.indices = append(selection.indices, uint32(i))
+			}
+			i++
+		}
+	} else {
+		selection.indices = make([]uint32, 0)
+		if emitNulls {
+			selection.valid = make([]bool, 0)
+		}
+		for i := 0; i < numValues; i++ {
+			if mask.IsNull(i) {
+				if emitNulls {
+					selection.indices = append(selection.indices, uint32(i))
+					selection.valid = append(selection.valid, false)
+				}
+				continue
+			}
+			if mask.Value(i) {
+				selection.indices = append(selection.indices, uint32(i))
+				if emitNulls {
+					selection.valid = append(selection.valid, true)
+				}
+			}
+		}
+		selection.numRows = len(selection.indices)
+	}
+
+	if selection.valid == nil && selection.numRows > 0 {
+		selection.runs = make([][2]int, 0)
+		start := int(selection.indices[0])
+		for i := 1; i <= len(selection.indices); i++ {
+			if i < len(selection.indices) && selection.indices[i] == selection.indices[i-1]+1 {
+				continue
+			}
+			selection.runs = append(selection.runs, [2]int{start, int(selection.indices[i-1]) + 1})
+			if i < len(selection.indices) {
+				start = int(selection.indices[i])
+			}
+		}
+	}
+	return selection
+}
+
+func (s *filterSelection) indicesArray(mem *memory.GoAllocator) *array.Uint32 {
+	b := array.NewUint32Builder(mem)
+	defer b.Release()
+	b.AppendValues(s.indices, s.valid)
+	return b.NewUint32Array()
+}
+
+/*
+Copies the selected rows of the array. Long runs of selected rows, which are
+common with unselective masks, are copied by concatenating slices of the array.
+*/
+func (s *filterSelection) filter(mem *memory.GoAllocator, arr arrow.Array, indices *array.Uint32) (arrow.Array, error) {
+	if s.runs == nil || len(s.runs)*filterMinAverageRunLength > s.numRows {
+		return TakeArray(mem, arr, indices)
+	}
+
+	slices := make([]arrow.Array, len(s.runs))
+	for i, run := range s.runs {
+		slices[i] = array.NewSlice(arr, int64(run[0]), int64(run[1]))
+		defer slices[i].Release()
+	}
+	filtered, err := array.Concatenate(slices, mem)
+	if err != nil {
+		return nil, errs.Wrap(err, fmt.Errorf("failed to concatenate %d slices", len(slices)))
+	}
+	return filtered, nil
+}
@@ -0,0 +1,234 @@
+package arrowops
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/apache/arrow/go/v17/arrow"
+	"github.com/apache/arrow/go/v17/arrow/array"
+	"github.com/apache/arrow/go/v17/arrow/memory"
+)
+
+func BenchmarkFilterRecord(b *testing.B) {
+	for _, size := range TEST_SIZES {
+		b.Run(fmt.Sprintf("size=%d", size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				mem := memory.NewGoAllocator()
+				r1 := MockData(mem, size, "random")
+				defer r1.Release()
+
+				// keep every 10th row
+				maskBuilder := array.NewBooleanBuilder(mem)
+				defer maskBuilder.Release()
+				for j := 0; j < size; j++ {
+					maskBuilder.Append(j%10 == 0)
+				}
+				mask := maskBuilder.NewBooleanArray()
+				defer mask.Release()
+				b.StartTimer()
+
+				filtered, err := FilterRecord(mem, r1, mask, NullSelectionDrop)
+				if err != nil {
+					b.Fatalf("received error while filtering record '%s'", err)
+				}
+				filtered.Release()
+			}
+		})
+	}
+}
+
+func TestFilterRecord(t *testing.T) {
+
+	mem := memory.NewGoAllocator()
+
+	recordBldr := func(ids []int64, valid []bool) arrow.Record {
+		recBuilder := array.NewRecordBuilder(mem, arrow.NewSchema(
+			[]arrow.Field{
+				{Name: "id", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+				{Name: "value", Type: arrow.BinaryTypes.String, Nullable: true},
+			}, nil),
+		)
+		defer recBuilder.Release()
+
+		values := make([]string, len(ids))
+		for i, id := range ids {
+			values[i] = fmt.Sprintf("v%d", id)
+		}
+		recBuilder.Field(0).(*array.Int64Builder).AppendValues(ids, valid)
+		recBuilder.Field(1).(*array.StringBuilder).AppendValues(values, valid)
+		return recBuilder.NewRecord()
+	}
+
+	longIDs := make([]int64, 100)
+	longMask := make([]bool, 100)
+	longExpectedIDs := make([]int64, 0)
+	for i := range longIDs {
+		longIDs[i] = int64(i)
+		// two long runs of selected rows
+		longMask[i] = i < 40 || i >= 60
+		if longMask[i] {
+			longExpectedIDs = append(longExpectedIDs, int64(i))
+		}
+	}
+
+	testCases := []struct {
+		caseName      string
+		ids           []int64
+		mask          []bool
+		maskValid     []bool
+		nullSelection NullSelection
+		expectedIDs   []int64
+		expectedValid []bool
+	}{
+		{
+			caseName:    "some_rows",
+			ids:         []int64{0, 1, 2, 3, 4},
+			mask:        []bool{true, false, true, false, true},
+			expectedIDs: []int64{0, 2, 4},
+		},
+		{
+			caseName:    "all_rows",
+			ids:         []int64{0, 1, 2},
+			mask:        []bool{true, true, true},
+			expectedIDs: []int64{0, 1, 2},
+		},
+		{
+			caseName:    "no_rows",
+			ids:         []int64{0, 1, 2},
+			mask:        []bool{false, false, false},
+			expectedIDs: []int64{},
+		},
+		{
+			caseName:    "empty",
+			ids:         []int64{},
+			mask:        []bool{},
+			expectedIDs: []int64{},
+		},
+		{
+			caseName:    "contiguous_rows",
+			ids:         []int64{0, 1, 2, 3, 4},
+			mask:        []bool{false, true, true, true, false},
+			expectedIDs: []int64{1, 2, 3},
+		},
+		{
+			caseName:    "long_runs",
+			ids:         longIDs,
+			mask:        longMask,
+			expectedIDs: longExpectedIDs,
+		},
+		{
+			caseName:      "drop_null_mask_values",
+			ids:           []int64{0, 1, 2, 3},
+			mask:          []bool{true, true, false, true},
+			maskValid:     []bool{true, false, true, true},
+			nullSelection: NullSelectionDrop,
+			expectedIDs:   []int64{0, 3},
+		},
+		{
+			caseName:      "emit_null_mask_values",
+			ids:           []int64{0, 1, 2, 3},
+			mask:          []bool{true, true, false, true},
+			maskValid:     []bool{true, false, true, true},
+			nullSelection: NullSelectionEmitNull,
+			expectedIDs:   []int64{0, 0, 3},
+			expectedValid: []bool{true, false, true},
+		},
+	}
+
+	for idx, tc := range testCases {
+		t.Run(fmt.Sprintf("case_%d:%s", idx, tc.caseName), func(t *testing.T) {
+			record := recordBldr(tc.ids, nil)
+			defer record.Release()
+
+			maskBuilder := array.NewBooleanBuilder(mem)
+			defer maskBuilder.Release()
+			maskBuilder.AppendValues(tc.mask, tc.maskValid)
+			mask := maskBuilder.NewBooleanArray()
+			defer mask.Release()
+
+			expected := recordBldr(tc.expectedIDs, tc.expectedValid)
+			defer expected.Release()
+
+			filtered, err := FilterRecord(mem, record, mask, tc.nullSelection)
+			if err != nil {
+				t.Fatalf("received unexpected error: %s", err)
+			}
+			defer filtered.Release()
+
+			diff, err := DiffRecords(expected, filtered, DiffOptions{})
+			if err != nil {
+				t.Fatalf("received unexpected error: %s", err)
+			}
+			if !diff.Equal() {
+				t.Errorf("expected filtered record to equal the expected record:\n%s", diff)
+			}
+
+			filteredArray, err := FilterArray(mem, record.Column(1), mask, tc.nullSelection)
+			if err != nil {
+				t.Fatalf("received unexpected error: %s", err)
+			}
+			defer filteredArray.Release()
+			if !array.Equal(expected.Column(1), filteredArray) {
+				t.Errorf("expected filtered array %v, got %v", expected.Column(1), filteredArray)
+			}
+		})
+	}
+
+}
+
+func TestFilterRecordWithSlicedMask(t *testing.T) {
+	mem := memory.NewGoAllocator()
+
+	maskValues := make([]bool, 43)
+	for i := range maskValues {
+		maskValues[i] = i == 5 || i == 33
+	}
+
+	record := MockData(mem, 40, "ascending")
+	defer record.Release()
+
+	maskBuilder := array.NewBooleanBuilder(mem)
+	defer maskBuilder.Release()
+	maskBuilder.AppendValues(maskValues, nil)
+	fullMask := maskBuilder.NewBooleanArray()
+	defer fullMask.Release()
+	mask := array.NewSlice(fullMask, 3, 43).(*array.Boolean)
+	defer mask.Release()
+
+	filtered, err := FilterRecord(mem, record, mask, NullSelectionDrop)
+	if err != nil {
+		t.Fatalf("received unexpected error: %s", err)
+	}
+	defer filtered.Release()
+
+	// the mask is offset by 3 rows so rows 2 and 30 are kept
+	expectedRows := []string{"2,2,2", "30,30,30"}
+	if actual := recordRowStrings(filtered); !slices.Equal(actual, expectedRows) {
+		t.Errorf("expected rows %v, got %v", expectedRows, actual)
+	}
+}
+
+func TestFilterRecordErrors(t *testing.T) {
+	mem := memory.NewGoAllocator()
+
+	record := MockData(mem, 3, "ascending")
+	defer record.Release()
+
+	maskBuilder := array.NewBooleanBuilder(mem)
+	defer maskBuilder.Release()
+	maskBuilder.AppendValues([]bool{true, false}, nil)
+	mask := maskBuilder.NewBooleanArray()
+	defer mask.Release()
+
+	_, err := FilterRecord(mem, record, mask, NullSelectionDrop)
+	if !errors.Is(err, ErrLengthsNotEqual) {
+		t.Errorf("expected error %v, got %v", ErrLengthsNotEqual, err)
+	}
+	_, err = FilterArray(mem, record.Column(0), mask, NullSelectionDrop)
+	if !errors.Is(err, ErrLengthsNotEqual) {
+		t.Errorf("expected error %v, got %v", ErrLengthsNotEqual, err)
+	}
+}
@@ -5,6 +5,7 @@ go 1.23.0
 require (
 	github.com/alekLukanen/errs v1.0.4
 	github.com/apache/arrow/go/v17 v17.0.0
+	github.com/zeebo/xxh3 v1.0.2
 )
 
 require (
@@ -20,7 +21,6 @@ require (
 	github.com/minio/asm2plan9s v0.0.0-20200509001527-cdd76441f9d8 // indirect
 	github.com/minio/c2goasm v0.0.0-20190812172519-36a3d3bbc4f3 // indirect
 	github.com/pierrec/lz4/v4 v4.1.21 // indirect
-	github.com/zeebo/xxh3 v1.0.2 // indirect
 	golang.org/x/exp v0.0.0-20240222234643-814bf88cf225 // indirect
 	golang.org/x/mod v0.18.0 // indirect
 	golang.org/x/net v0.26.0 // indirect
@@ -0,0 +1,126 @@
+package arrowops
+
+import (
+	"fmt"
+
+	"github.com/alekLukanen/errs"
+	"github.com/apache/arrow/go/v17/arrow"
+	"github.com/apache/arrow/go/v17/arrow/memory"
+)
+
+/*
+A record grouped by a set of key columns, created with GroupBy. The
+record must not be released before Aggregate is called.
+*/
+type GroupedRecord struct {
+	mem    *memory.GoAllocator
+	record arrow.Record
+	keys   []string
+}
+
+/*
+Groups the rows of the record by the values in the key columns. The groups are
+computed when Aggregate is called, for example:
+
+	GroupBy(mem, record, []string{"user"}).Aggregate(Sum("amount"), Count(CountAllColumns))
+*/
+func GroupBy(mem *memory.GoAllocator, record arrow.Record, keys []string) *GroupedRecord {
+	return &GroupedRecord{mem: mem, record: record, keys: keys}
+}
+
+/*
+Computes the aggregations for each group using a hash table. The returned record
+has one row for each group, in the order the groups first appear in the record,
+containing the key columns followed by a column for each aggregation. Null
+values in the key columns are grouped together.
+*/
+func (g *GroupedRecord) Aggregate(aggregations ...Aggregation) (arrow.Record, error) {
+	g.record.Retain()
+	defer g.record.Release()
+
+	groups, err := hashGroupRows(g.record, g.keys)
+	if err != nil {
+		return nil, errs.Wrap(err, fmt.Errorf("failed to group record by keys %v", g.keys))
+	}
+
+	aggregators, columnIdxs, err := newAggregators(g.record.Schema(), aggregations)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		for _, agg := range aggregators {
+			agg.release()
+		}
+	}()
+
+	results := make([]arrow.Array, 0, len(aggregators))
+	defer func() {
+		for _, result := range results {
+			result.Release()
+		}
+	}()
+	for idx, agg := range aggregators {
+		var column arrow.Array
+		if columnIdxs[idx] != -1 {
+			column = g.record.Column(columnIdxs[idx])
+		}
+		agg.resize(groups.numGroups())
+		agg.update(column, groups.groupIDs)
+		result, err := agg.flush(g.mem, groups.numGroups())
+		if err != nil {
+			return nil, errs.Wrap(err, fmt.Errorf("failed to aggregate %s", aggregations[idx].Name()))
+		}
+		results = append(results, result)
+	}
+
+	keys, err := takeGroupKeys(g.mem, g.record, g.keys, groups.firstRows)
+	if err != nil {
+		return nil, err
+	}
+	defer keys.Release()
+
+	return aggregateOutput(keys, aggregations, results), nil
+}
+
+/*
+Computes the aggregations for each group in one pass over a record sorted by the
+keys, see SortedAggregator, instead of building a hash table. The returned record
+is in the same format as Aggregate, with the groups in key order.
+*/
+func (g *GroupedRecord) AggregateSorted(aggregations ...Aggregation) (arrow.Record, error) {
+	aggregator, err := NewSortedAggregator(g.mem, g.record.Schema(), g.keys, aggregations...)
+	if err != nil {
+		return nil, err
+	}
+	defer aggregator.Release()
+
+	closedGroups, err := aggregator.Aggregate(g.record)
+	if err != nil {
+		return nil, err
+	}
+	defer closedGroups.Release()
+	lastGroup, err := aggregator.Finish()
+	if err != nil {
+		return nil, err
+	}
+	defer lastGroup.Release()
+
+	return ConcatenateRecords(g.mem, closedGroups, lastGroup)
+}
+
+/*
+Takes the key columns of the rows provided, one row for each group.
+*/
+func takeGroupKeys(mem *memory.GoAllocator, record arrow.Record, keys []string, rows []uint32) (arrow.Record, error) {
+	keyRecord, err := TakeRecordColumns(record, keys)
+	if err != nil {
+		return nil, errs.Wrap(err, fmt.Errorf("failed to take columns %v", keys))
+	}
+	defer keyRecord.Release()
+
+	groupKeys, err := takeRecordRows(mem, keyRecord, rows)
+	if err != nil {
+		return nil, errs.Wrap(err, fmt.Errorf("failed to take %d rows from record", len(rows)))
+	}
+	return groupKeys, nil
+}
@@ -0,0 +1,160 @@
+package arrowops
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/apache/arrow/go/v17/arrow"
+	"github.com/apache/arrow/go/v17/arrow/memory"
+)
+
+func BenchmarkGroupBy(b *testing.B) {
+	for _, size := range TEST_SIZES {
+		b.Run(fmt.Sprintf("size=%d", size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				mem := memory.NewGoAllocator()
+				r1 := MockData(mem, size, "random")
+				defer r1.Release()
+				b.StartTimer()
+
+				result, err := GroupBy(mem, r1, []string{"b"}).Aggregate(Sum("a"), Count(CountAllColumns), Max("c"))
+				if err != nil {
+					b.Fatalf("received error while aggregating record '%s'", err)
+				}
+				result.Release()
+			}
+		})
+	}
+}
+
+func TestGroupByAggregate(t *testing.T) {
+	mem := memory.NewGoAllocator()
+
+	record := recordFromJSON(t, mem, arrow.NewSchema(
+		[]arrow.Field{
+			{Name: "user", Type: arrow.BinaryTypes.String, Nullable: true},
+			{Name: "amount", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+			{Name: "ts", Type: arrow.PrimitiveTypes.Int32},
+			{Name: "latency", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+		}, nil), `[
+			{"user": "u1", "amount": 10, "ts": 5, "latency": 1},
+			{"user": "u2", "amount": 20, "ts": 3, "latency": 2},
+			{"user": "u1", "amount": null, "ts": 1, "latency": 3},
+			{"user": null, "amount": 5, "ts": 7, "latency": null},
+			{"user": "u1", "amount": 30, "ts": 9, "latency": 5},
+			{"user": null, "amount": 1, "ts": 2, "latency": 4},
+			{"user": "u2", "amount": 20, "ts": 8, "latency": null}
+		]`,
+	)
+	defer record.Release()
+
+	result, err := GroupBy(mem, record, []string{"user"}).Aggregate(
+		Sum("amount"),
+		Count(CountAllColumns),
+		Count("amount"),
+		Min("ts"),
+		Max("ts"),
+		Mean("latency"),
+		CountDistinct("amount").As("distinct_amounts"),
+	)
+	if err != nil {
+		t.Fatalf("received unexpected error: %s", err)
+	}
+	defer result.Release()
+
+	expectedColumns := []string{
+		"user", "sum(amount)", "count(*)", "count(amount)", "min(ts)", "max(ts)", "mean(latency)", "distinct_amounts",
+	}
+	columns := make([]string, result.NumCols())
+	for i := range columns {
+		columns[i] = result.ColumnName(i)
+	}
+	if !slices.Equal(columns, expectedColumns) {
+		t.Errorf("expected columns %v, got %v", expectedColumns, columns)
+	}
+
+	expectedRows := []string{
+		"u1,40,3,2,1,9,3,2",
+		"u2,40,2,2,3,8,2,1",
+		"(null),6,2,2,2,7,4,2",
+	}
+	if actual := recordRowStrings(result); !slices.Equal(actual, expectedRows) {
+		t.Errorf("expected rows %v, got %v", expectedRows, actual)
+	}
+}
+
+func TestGroupByAggregateGroupsWithoutValues(t *testing.T) {
+	mem := memory.NewGoAllocator()
+
+	record := recordFromJSON(t, mem, arrow.NewSchema(
+		[]arrow.Field{
+			{Name: "user", Type: arrow.BinaryTypes.String, Nullable: true},
+			{Name: "amount", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+			{Name: "ts", Type: arrow.PrimitiveTypes.Int32},
+			{Name: "latency", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+		}, nil), `[
+			{"user": "u1", "amount": 10, "ts": 5, "latency": 1},
+			{"user": "u2", "amount": 20, "ts": 3, "latency": 2},
+			{"user": "u1", "amount": null, "ts": 1, "latency": 3},
+			{"user": null, "amount": 5, "ts": 7, "latency": null},
+			{"user": "u1", "amount": 30, "ts": 9, "latency": 5},
+			{"user": null, "amount": 1, "ts": 2, "latency": 4},
+			{"user": "u2", "amount": 20, "ts": 8, "latency": null}
+		]`,
+	)
+	defer record.Release()
+
+	// the only row with user u1 and ts 1 has a null amount
+	result, err := GroupBy(mem, record, []string{"user", "ts"}).Aggregate(
+		Sum("amount"), Min("amount"), Mean("amount"), Count("amount"),
+	)
+	if err != nil {
+		t.Fatalf("received unexpected error: %s", err)
+	}
+	defer result.Release()
+
+	if result.NumRows() != record.NumRows() {
+		t.Fatalf("expected %d groups, got %d", record.NumRows(), result.NumRows())
+	}
+	if actual := recordRowStrings(result)[2]; actual != "u1,1,(null),(null),(null),0" {
+		t.Errorf("expected null aggregates for the group without values, got %s", actual)
+	}
+}
+
+func TestGroupByAggregateErrors(t *testing.T) {
+	mem := memory.NewGoAllocator()
+
+	record := recordFromJSON(t, mem, arrow.NewSchema(
+		[]arrow.Field{
+			{Name: "user", Type: arrow.BinaryTypes.String, Nullable: true},
+			{Name: "amount", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+			{Name: "ts", Type: arrow.PrimitiveTypes.Int32},
+		}, nil),
+		`[{"user": "u1", "amount": 10, "ts": 5}]`,
+	)
+	defer record.Release()
+
+	testCases := []struct {
+		caseName     string
+		keys         []string
+		aggregations []Aggregation
+		expectedErr  error
+	}{
+		{caseName: "no_keys", aggregations: []Aggregation{Count(CountAllColumns)}, expectedErr: ErrColumnNamesRequired},
+		{caseName: "key_not_found", keys: []string{"z"}, expectedErr: ErrColumnNotFound},
+		{caseName: "column_not_found", keys: []string{"user"}, aggregations: []Aggregation{Sum("z")}, expectedErr: ErrColumnNotFound},
+		{caseName: "sum_of_strings", keys: []string{"ts"}, aggregations: []Aggregation{Sum("user")}, expectedErr: ErrUnsupportedDataType},
+	}
+
+	for idx, tc := range testCases {
+		t.Run(fmt.Sprintf("case_%d:%s", idx, tc.caseName), func(t *testing.T) {
+			_, err := GroupBy(mem, record, tc.keys).Aggregate(tc.aggregations...)
+			if !errors.Is(err, tc.expectedErr) {
+				t.Errorf("expected error %v, got %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package arrowops
+
+import (
+	"fmt"
+
+	"github.com/alekLukanen/errs"
+	"github.com/apache/arrow/go/v17/arrow"
+)
+
+/*
+The result of grouping the rows of a record by the values of a set of
+columns. Group ids are assigned in the order the groups are first seen.
+*/
+type rowGroups struct {
+	// the group id of each row in the record
+	groupIDs []uint32
+	// the index of the first row of each group
+	firstRows []uint32
+}
+
+func (g *rowGroups) numGroups() int {
+	return len(g.firstRows)
+}
+
+/*
+Groups the rows of the record by the values in the columns using a hash table.
+Rows with equal hashes are compared with a RowComparator so hash collisions
+never merge different groups. Null values are grouped together.
+*/
+func hashGroupRows(record arrow.Record, columns []string) (*rowGroups, error) {
+	if len(columns) == 0 {
+		return nil, errs.NewStackError(ErrColumnNamesRequired)
+	}
+
+	columnIdxs, err := hashColumnIndices(record.Schema(), columns)
+	if err != nil {
+		return nil, err
+	}
+	comparator, err := newRowComparator(record.Schema(), record.Schema(), columnIdxs, columnIdxs, SortKeysFromColumns(columns...))
+	if err != nil {
+		return nil, errs.Wrap(err, fmt.Errorf("failed to create comparator for columns %v", columns))
+	}
+	hashes, err := hashRecordRows(record, columnIdxs, DefaultHashSeed)
+	if err != nil {
+		return nil, errs.Wrap(err, fmt.Errorf("failed to hash columns %v", columns))
+	}
+
+	numRows := int(record.NumRows())
+	groups := &rowGroups{
+		groupIDs:  make([]uint32, numRows),
+		firstRows: make([]uint32, 0),
+	}
+
+	// the most recent group for each hash, groups with the same
+	// hash are chained together through nextGroup
+	hashGroups := make(map[uint64]uint32)
+	nextGroup := make([]int32, 0)
+
+	for i := 0; i < numRows; i++ {
+		hash := hashes[i]
+		group := int32(-1)
+		headGroup, hashSeen := hashGroups[hash]
+		if hashSeen {
+			for g := int32(headGroup); g != -1; g = nextGroup[g] {
+				if comparator.Equal(record, record, int(groups.firstRows[g]), i) {
+					group = g
+					break
+				}
+			}
+		}
+
+		if group == -1 {
+			group = int32(len(groups.firstRows))
+			groups.firstRows = append(groups.firstRows, uint32(i))
+			if hashSeen {
+				nextGroup = append(nextGroup, int32(headGroup))
+			} else {
+				nextGroup = append(nextGroup, -1)
+			}
+			hashGroups[hash] = uint32(group)
+		}
+		groups.groupIDs[i] = uint32(group)
+	}
+
+	return groups, nil
+}
@@ -0,0 +1,94 @@
+package arrowops
+
+import (
+	"fmt"
+
+	"github.com/alekLukanen/errs"
+	"github.com/apache/arrow/go/v17/arrow"
+	"github.com/apache/arrow/go/v17/arrow/memory"
+)
+
+/*
+Joins the left and right records where the values of the left key columns are
+equal to the values of the right key columns, using the default join options.
+*/
+func HashJoin(mem *memory.GoAllocator, left, right arrow.Record, leftKeys, rightKeys []string, joinType JoinType) (arrow.Record, error) {
+	return HashJoinWithOptions(mem, left, right, leftKeys, rightKeys, joinType, JoinOptions{})
+}
+
+/*
+Joins the left and right records where the values of the left key columns are
+equal to the values of the right key columns. A hash table is built from the
+right record and probed with each left row, so the right record should be the
+smaller of the two. Key columns must have the same data types in both records
+and rows with a null key never match. Output rows are ordered by the left
+row and then the right row, with unmatched right rows of right and full joins
+at the end.
+*/
+func HashJoinWithOptions(mem *memory.GoAllocator, left, right arrow.Record, leftKeys, rightKeys []string, joinType JoinType, opts JoinOptions) (arrow.Record, error) {
+	left.Retain()
+	defer left.Release()
+	right.Retain()
+	defer right.Release()
+
+	leftIdxs, rightIdxs, err := joinKeyIndices(left.Schema(), right.Schema(), leftKeys, rightKeys)
+	if err != nil {
+		return nil, err
+	}
+	comparator, err := newRowComparator(left.Schema(), right.Schema(), leftIdxs, rightIdxs, SortKeysFromColumns(leftKeys...))
+	if err != nil {
+		return nil, errs.Wrap(err, fmt.Errorf("failed to create comparator for keys %v and %v", leftKeys, rightKeys))
+	}
+	leftHashes, err := hashRecordRows(left, leftIdxs, DefaultHashSeed)
+	if err != nil {
+		return nil, errs.Wrap(err, fmt.Errorf("failed to hash left keys %v", leftKeys))
+	}
+	rightHashes, err := hashRecordRows(right, rightIdxs, DefaultHashSeed)
+	if err != nil {
+		return nil, errs.Wrap(err, fmt.Errorf("failed to hash right keys %v", rightKeys))
+	}
+
+	rightRowsByHash := make(map[uint64][]uint32)
+	for i, hash := range rightHashes {
+		if joinKeyIsNull(right, rightIdxs, i) {
+			continue
+		}
+		rightRowsByHash[hash] = append(rightRowsByHash[hash], uint32(i))
+	}
+
+	indices := newJoinIndices()
+	rightMatched := make([]bool, right.NumRows())
+	for i, hash := range leftHashes {
+		matched := false
+		if !joinKeyIsNull(left, leftIdxs, i) {
+			for _, rightRow := range rightRowsByHash[hash] {
+				if !comparator.Equal(left, right, i, int(rightRow)) {
+					continue
+				}
+				matched = true
+				if joinType == SemiJoin || joinType == AntiJoin {
+					break
+				}
+				indices.appendMatch(i, int(rightRow))
+				rightMatched[rightRow] = true
+			}
+		}
+
+		switch {
+		case joinType == SemiJoin && matched,
+			joinType == AntiJoin && !matched,
+			(joinType == LeftJoin || joinType == FullJoin) && !matched:
+			indices.appendLeftOnly(i)
+		}
+	}
+
+	if joinType == RightJoin || joinType == FullJoin {
+		for i, matched := range rightMatched {
+			if !matched {
+				indices.appendRightOnly(i)
+			}
+		}
+	}
+
+	return joinOutput(mem, left, right, joinType, indices, opts)
+}
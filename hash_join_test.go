@@ -0,0 +1,203 @@
+package arrowops
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/apache/arrow/go/v17/arrow"
+	"github.com/apache/arrow/go/v17/arrow/memory"
+)
+
+func BenchmarkHashJoin(b *testing.B) {
+	for _, size := range TEST_SIZES {
+		b.Run(fmt.Sprintf("size=%d", size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				mem := memory.NewGoAllocator()
+				r1 := MockData(mem, size, "random")
+				defer r1.Release()
+				r2 := MockData(mem, size, "random")
+				defer r2.Release()
+				b.StartTimer()
+
+				joined, err := HashJoin(mem, r1, r2, []string{"a"}, []string{"a"}, InnerJoin)
+				if err != nil {
+					b.Fatalf("received error while joining records '%s'", err)
+				}
+				joined.Release()
+			}
+		})
+	}
+}
+
+/*
+Each row of the record as its comma separated values.
+*/
+func recordRowStrings(record arrow.Record) []string {
+	rows := make([]string, record.NumRows())
+	for i := range rows {
+		values := make([]string, record.NumCols())
+		for j := range values {
+			values[j] = record.Column(j).ValueStr(i)
+		}
+		rows[i] = strings.Join(values, ",")
+	}
+	return rows
+}
+
+func TestHashJoin(t *testing.T) {
+
+	mem := memory.NewGoAllocator()
+
+	schema := arrow.NewSchema(
+		[]arrow.Field{
+			{Name: "id", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+			{Name: "name", Type: arrow.BinaryTypes.String},
+		}, nil)
+
+	left := recordFromJSON(t, mem, schema, `[{"id": 1, "name": "a"}, {"id": 2, "name": "b"}, {"id": 2, "name": "c"}, {"id": 3, "name": "d"}, {"id": null, "name": "e"}]`)
+	defer left.Release()
+	right := recordFromJSON(t, mem, schema, `[{"id": 2, "name": "x"}, {"id": 3, "name": "y"}, {"id": 3, "name": "z"}, {"id": 4, "name": "w"}, {"id": null, "name": "v"}]`)
+	defer right.Release()
+
+	joinedColumns := []string{"id_left", "name_left", "id_right", "name_right"}
+	testCases := []struct {
+		joinType        JoinType
+		expectedColumns []string
+		expectedRows    []string
+	}{
+		{
+			joinType:        InnerJoin,
+			expectedColumns: joinedColumns,
+			expectedRows:    []string{"2,b,2,x", "2,c,2,x", "3,d,3,y", "3,d,3,z"},
+		},
+		{
+			joinType:        LeftJoin,
+			expectedColumns: joinedColumns,
+			expectedRows: []string{
+				"1,a,(null),(null)", "2,b,2,x", "2,c,2,x", "3,d,3,y", "3,d,3,z", "(null),e,(null),(null)",
+			},
+		},
+		{
+			joinType:        RightJoin,
+			expectedColumns: joinedColumns,
+			expectedRows: []string{
+				"2,b,2,x", "2,c,2,x", "3,d,3,y", "3,d,3,z", "(null),(null),4,w", "(null),(null),(null),v",
+			},
+		},
+		{
+			joinType:        FullJoin,
+			expectedColumns: joinedColumns,
+			expectedRows: []string{
+				"1,a,(null),(null)", "2,b,2,x", "2,c,2,x", "3,d,3,y", "3,d,3,z", "(null),e,(null),(null)",
+				"(null),(null),4,w", "(null),(null),(null),v",
+			},
+		},
+		{
+			joinType:        SemiJoin,
+			expectedColumns: []string{"id", "name"},
+			expectedRows:    []string{"2,b", "2,c", "3,d"},
+		},
+		{
+			joinType:        AntiJoin,
+			expectedColumns: []string{"id", "name"},
+			expectedRows:    []string{"1,a", "(null),e"},
+		},
+	}
+
+	for idx, tc := range testCases {
+		t.Run(fmt.Sprintf("case_%d:%s", idx, tc.joinType), func(t *testing.T) {
+			joined, err := HashJoin(mem, left, right, []string{"id"}, []string{"id"}, tc.joinType)
+			if err != nil {
+				t.Fatalf("received unexpected error: %s", err)
+			}
+			defer joined.Release()
+
+			columns := make([]string, joined.NumCols())
+			for i := range columns {
+				columns[i] = joined.ColumnName(i)
+			}
+			if !slices.Equal(columns, tc.expectedColumns) {
+				t.Errorf("expected columns %v, got %v", tc.expectedColumns, columns)
+			}
+			if actual := recordRowStrings(joined); !slices.Equal(actual, tc.expectedRows) {
+				t.Errorf("expected rows %v, got %v", tc.expectedRows, actual)
+			}
+		})
+	}
+
+}
+
+func TestHashJoinWithOptions(t *testing.T) {
+	mem := memory.NewGoAllocator()
+
+	schema := arrow.NewSchema(
+		[]arrow.Field{
+			{Name: "id", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+			{Name: "name", Type: arrow.BinaryTypes.String},
+		}, nil)
+
+	left := recordFromJSON(t, mem, schema, `[{"id": 1, "name": "a"}, {"id": 2, "name": "b"}]`)
+	defer left.Release()
+	right := recordFromJSON(t, mem, schema, `[{"id": 2, "name": "x"}, {"id": 1, "name": "y"}]`)
+	defer right.Release()
+
+	joined, err := HashJoinWithOptions(mem, left, right, []string{"id", "name"}, []string{"id", "name"}, LeftJoin, JoinOptions{
+		LeftSuffix:  ".l",
+		RightSuffix: ".r",
+	})
+	if err != nil {
+		t.Fatalf("received unexpected error: %s", err)
+	}
+	defer joined.Release()
+
+	if joined.ColumnName(0) != "id.l" || joined.ColumnName(3) != "name.r" {
+		t.Errorf("expected suffixed column names, got %s", joined.Schema())
+	}
+	if !joined.Schema().Field(2).Nullable {
+		t.Errorf("expected right columns of a left join to be nullable")
+	}
+	expectedRows := []string{"1,a,(null),(null)", "2,b,(null),(null)"}
+	if actual := recordRowStrings(joined); !slices.Equal(actual, expectedRows) {
+		t.Errorf("expected rows %v, got %v", expectedRows, actual)
+	}
+}
+
+func TestHashJoinErrors(t *testing.T) {
+	mem := memory.NewGoAllocator()
+
+	schema := arrow.NewSchema(
+		[]arrow.Field{
+			{Name: "id", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+			{Name: "name", Type: arrow.BinaryTypes.String},
+		}, nil)
+
+	left := recordFromJSON(t, mem, schema, `[{"id": 1, "name": "a"}, {"id": 2, "name": "b"}]`)
+	defer left.Release()
+	right := recordFromJSON(t, mem, schema, `[{"id": 2, "name": "x"}, {"id": 1, "name": "y"}]`)
+	defer right.Release()
+
+	testCases := []struct {
+		caseName    string
+		leftKeys    []string
+		rightKeys   []string
+		expectedErr error
+	}{
+		{caseName: "no_keys", expectedErr: ErrColumnNamesRequired},
+		{caseName: "different_number_of_keys", leftKeys: []string{"id"}, rightKeys: []string{"id", "name"}, expectedErr: ErrLengthsNotEqual},
+		{caseName: "column_not_found", leftKeys: []string{"z"}, rightKeys: []string{"id"}, expectedErr: ErrColumnNotFound},
+		{caseName: "different_types", leftKeys: []string{"id"}, rightKeys: []string{"name"}, expectedErr: ErrDataTypesNotEqual},
+	}
+
+	for idx, tc := range testCases {
+		t.Run(fmt.Sprintf("case_%d:%s", idx, tc.caseName), func(t *testing.T) {
+			_, err := HashJoin(mem, left, right, tc.leftKeys, tc.rightKeys, InnerJoin)
+			if !errors.Is(err, tc.expectedErr) {
+				t.Errorf("expected error %v, got %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,217 @@
+package arrowops
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"github.com/alekLukanen/errs"
+	"github.com/apache/arrow/go/v17/arrow"
+	"github.com/apache/arrow/go/v17/arrow/array"
+	"github.com/apache/arrow/go/v17/arrow/memory"
+	"github.com/zeebo/xxh3"
+)
+
+/*
+The seed used by HashRecordRows. Hashes created with the same seed are
+stable across records and processes so they can be persisted.
+*/
+const DefaultHashSeed uint64 = 0x2545F4914F6CDD1D
+
+const hashNullSalt uint64 = 0x9E3779B97F4A7C15
+
+type columnHasher func(arr arrow.Array, hashes []uint64)
+
+/*
+Hashes the values of the provided columns for each row in the record using xxh3.
+If no columns are provided all columns are hashed. Rows that compare as equal
+with CompareRecordRows have the same hash, so null values have a hash of their
+own, -0.0 hashes the same as 0.0 and all NaN values share the same hash. Integers
+and temporal values hash the same regardless of their width so equal values in
+columns of different integer types have the same hash.
+*/
+func HashRecordRows(mem *memory.GoAllocator, record arrow.Record, columns ...string) (*array.Uint64, error) {
+	return HashRecordRowsWithSeed(mem, record, DefaultHashSeed, columns...)
+}
+
+/*
+Hashes the rows in the same way as HashRecordRows using the seed provided.
+*/
+func HashRecordRowsWithSeed(mem *memory.GoAllocator, record arrow.Record, seed uint64, columns ...string) (*array.Uint64, error) {
+	record.Retain()
+	defer record.Release()
+
+	columnIdxs, err := hashColumnIndices(record.Schema(), columns)
+	if err != nil {
+		return nil, err
+	}
+
+	hashes, err := hashRecordRows(record, columnIdxs, seed)
+	if err != nil {
+		return nil, err
+	}
+
+	b := array.NewUint64Builder(mem)
+	defer b.Release()
+	b.AppendValues(hashes, nil)
+	return b.NewUint64Array(), nil
+}
+
+func hashColumnIndices(schema *arrow.Schema, columns []string) ([]int, error) {
+	if len(columns) == 0 {
+		columnIdxs := make([]int, schema.NumFields())
+		for i := range columnIdxs {
+			columnIdxs[i] = i
+		}
+		return columnIdxs, nil
+	}
+
+	columnIdxs := make([]int, len(columns))
+	for idx, column := range columns {
+		fieldIdxs := schema.FieldIndices(column)
+		if len(fieldIdxs) == 0 {
+			return nil, errs.NewStackError(fmt.Errorf("%w| column name: %s", ErrColumnNotFound, column))
+		}
+		columnIdxs[idx] = fieldIdxs[0]
+	}
+	return columnIdxs, nil
+}
+
+func hashRecordRows(record arrow.Record, columnIdxs []int, seed uint64) ([]uint64, error) {
+	hashes := make([]uint64, record.NumRows())
+	for i := range hashes {
+		hashes[i] = seed
+	}
+	for _, columnIdx := range columnIdxs {
+		column := record.Column(columnIdx)
+		hasher, err := newColumnHasher(column.DataType())
+		if err != nil {
+			return nil, errs.Wrap(err, fmt.Errorf("failed to hash column %s", record.ColumnName(columnIdx)))
+		}
+		hasher(column, hashes)
+	}
+	return hashes, nil
+}
+
+func newColumnHasher(dataType arrow.DataType) (columnHasher, error) {
+	switch dataType.ID() {
+	case arrow.BOOL:
+		return hashFixedWidthColumn(func(arr arrow.Array, i int) uint64 {
+			if arr.(*array.Boolean).Value(i) {
+				return 1
+			}
+			return 0
+		}), nil
+	case arrow.INT8:
+		return hashIntColumn[int8, *array.Int8](), nil
+	case arrow.INT16:
+		return hashIntColumn[int16, *array.Int16](), nil
+	case arrow.INT32:
+		return hashIntColumn[int32, *array.Int32](), nil
+	case arrow.INT64:
+		return hashIntColumn[int64, *array.Int64](), nil
+	case arrow.UINT8:
+		return hashUintColumn[uint8, *array.Uint8](), nil
+	case arrow.UINT16:
+		return hashUintColumn[uint16, *array.Uint16](), nil
+	case arrow.UINT32:
+		return hashUintColumn[uint32, *array.Uint32](), nil
+	case arrow.UINT64:
+		return hashUintColumn[uint64, *array.Uint64](), nil
+	case arrow.FLOAT16:
+		return hashFixedWidthColumn(func(arr arrow.Array, i int) uint64 {
+			return normalizedFloatBits(float64(arr.(*array.Float16).Value(i).Float32()))
+		}), nil
+	case arrow.FLOAT32:
+		return hashFixedWidthColumn(func(arr arrow.Array, i int) uint64 {
+			return normalizedFloatBits(float64(arr.(*array.Float32).Value(i)))
+		}), nil
+	case arrow.FLOAT64:
+		return hashFixedWidthColumn(func(arr arrow.Array, i int) uint64 {
+			return normalizedFloatBits(arr.(*array.Float64).Value(i))
+		}), nil
+	case arrow.STRING:
+		return hashStringColumn[*array.String](), nil
+	case arrow.LARGE_STRING:
+		return hashStringColumn[*array.LargeString](), nil
+	case arrow.BINARY, arrow.LARGE_BINARY:
+		return func(arr arrow.Array, hashes []uint64) {
+			a := arr.(binaryValueArray)
+			for i := range hashes {
+				if a.IsNull(i) {
+					hashes[i] = hashNull(hashes[i])
+				} else {
+					hashes[i] = xxh3.HashSeed(a.Value(i), hashes[i])
+				}
+			}
+		}, nil
+	case arrow.DATE32:
+		return hashIntColumn[arrow.Date32, *array.Date32](), nil
+	case arrow.DATE64:
+		return hashIntColumn[arrow.Date64, *array.Date64](), nil
+	case arrow.TIMESTAMP:
+		return hashIntColumn[arrow.Timestamp, *array.Timestamp](), nil
+	case arrow.TIME32:
+		return hashIntColumn[arrow.Time32, *array.Time32](), nil
+	case arrow.TIME64:
+		return hashIntColumn[arrow.Time64, *array.Time64](), nil
+	case arrow.DURATION:
+		return hashIntColumn[arrow.Duration, *array.Duration](), nil
+	default:
+		return nil, errs.NewStackError(fmt.Errorf("%w| %s", ErrUnsupportedDataType, dataType))
+	}
+}
+
+func hashNull(hash uint64) uint64 {
+	return xxh3.HashSeed(nil, hash^hashNullSalt)
+}
+
+func hashFixedWidthColumn(value func(arr arrow.Array, i int) uint64) columnHasher {
+	return func(arr arrow.Array, hashes []uint64) {
+		var buf [8]byte
+		for i := range hashes {
+			if arr.IsNull(i) {
+				hashes[i] = hashNull(hashes[i])
+				continue
+			}
+			binary.LittleEndian.PutUint64(buf[:], value(arr, i))
+			hashes[i] = xxh3.HashSeed(buf[:], hashes[i])
+		}
+	}
+}
+
+func hashIntColumn[T integer, E valueArray[T]]() columnHasher {
+	return hashFixedWidthColumn(func(arr arrow.Array, i int) uint64 {
+		return uint64(int64(arr.(E).Value(i)))
+	})
+}
+
+func hashUintColumn[T unsignedInteger, E valueArray[T]]() columnHasher {
+	return hashFixedWidthColumn(func(arr arrow.Array, i int) uint64 {
+		return uint64(arr.(E).Value(i))
+	})
+}
+
+func hashStringColumn[E valueArray[string]]() columnHasher {
+	return func(arr arrow.Array, hashes []uint64) {
+		a := arr.(E)
+		for i := range hashes {
+			if a.IsNull(i) {
+				hashes[i] = hashNull(hashes[i])
+			} else {
+				hashes[i] = xxh3.HashStringSeed(a.Value(i), hashes[i])
+			}
+		}
+	}
+}
+
+func normalizedFloatBits(v float64) uint64 {
+	if math.IsNaN(v) {
+		return math.Float64bits(math.NaN())
+	}
+	if v == 0 {
+		// treat -0.0 and 0.0 as the same value
+		return 0
+	}
+	return math.Float64bits(v)
+}
@@ -0,0 +1,156 @@
+package arrowops
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/apache/arrow/go/v17/arrow"
+	"github.com/apache/arrow/go/v17/arrow/array"
+	"github.com/apache/arrow/go/v17/arrow/memory"
+)
+
+func BenchmarkHashRecordRows(b *testing.B) {
+	for _, size := range TEST_SIZES {
+		b.Run(fmt.Sprintf("size=%d", size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				mem := memory.NewGoAllocator()
+				r1 := MockData(mem, size, "random")
+				defer r1.Release()
+				b.StartTimer()
+				hashes, err := HashRecordRows(mem, r1)
+				if err != nil {
+					b.Fatalf("received error while hashing record '%s'", err)
+				}
+				hashes.Release()
+			}
+		})
+	}
+}
+
+func TestHashRecordRows(t *testing.T) {
+
+	mem := memory.NewGoAllocator()
+
+	recBuilder := array.NewRecordBuilder(
+		mem, arrow.NewSchema(
+			[]arrow.Field{
+				{Name: "a", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+				{Name: "b", Type: arrow.BinaryTypes.String, Nullable: true},
+				{Name: "c", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+			}, nil),
+	)
+	defer recBuilder.Release()
+
+	recBuilder.Field(0).(*array.Int64Builder).AppendValues([]int64{1, 1, 2, 0, 0, 1}, []bool{true, true, true, false, false, true})
+	recBuilder.Field(1).(*array.StringBuilder).AppendValues([]string{"a", "a", "a", "", "", "b"}, []bool{true, true, true, true, false, true})
+	recBuilder.Field(2).(*array.Float64Builder).AppendValues([]float64{0, math.Copysign(0, -1), math.NaN(), math.NaN(), 1, 1}, nil)
+	record := recBuilder.NewRecord()
+	defer record.Release()
+
+	testCases := []struct {
+		caseName    string
+		columns     []string
+		equalRows   [][2]int
+		unequalRows [][2]int
+	}{
+		{
+			caseName:    "all_columns",
+			columns:     nil,
+			equalRows:   [][2]int{{0, 1}},
+			unequalRows: [][2]int{{0, 2}, {3, 4}, {1, 5}},
+		},
+		{
+			caseName:    "single_column",
+			columns:     []string{"a"},
+			equalRows:   [][2]int{{0, 1}, {0, 5}, {3, 4}},
+			unequalRows: [][2]int{{0, 2}, {2, 3}},
+		},
+		{
+			caseName:    "null_is_not_empty_string",
+			columns:     []string{"b"},
+			equalRows:   [][2]int{{0, 2}},
+			unequalRows: [][2]int{{3, 4}, {0, 5}},
+		},
+		{
+			caseName:    "normalized_floats",
+			columns:     []string{"c"},
+			equalRows:   [][2]int{{0, 1}, {2, 3}, {4, 5}},
+			unequalRows: [][2]int{{0, 2}, {0, 4}},
+		},
+	}
+
+	for idx, tc := range testCases {
+		t.Run(fmt.Sprintf("case_%d:%s", idx, tc.caseName), func(t *testing.T) {
+			hashes, err := HashRecordRows(mem, record, tc.columns...)
+			if err != nil {
+				t.Fatalf("received unexpected error: %s", err)
+			}
+			defer hashes.Release()
+
+			if hashes.Len() != int(record.NumRows()) {
+				t.Fatalf("expected %d hashes, got %d", record.NumRows(), hashes.Len())
+			}
+			for _, rows := range tc.equalRows {
+				if hashes.Value(rows[0]) != hashes.Value(rows[1]) {
+					t.Errorf("expected rows %d and %d to have the same hash", rows[0], rows[1])
+				}
+			}
+			for _, rows := range tc.unequalRows {
+				if hashes.Value(rows[0]) == hashes.Value(rows[1]) {
+					t.Errorf("expected rows %d and %d to have different hashes", rows[0], rows[1])
+				}
+			}
+		})
+	}
+
+}
+
+func TestHashRecordRowsIsConsistentAcrossRecords(t *testing.T) {
+	mem := memory.NewGoAllocator()
+
+	r1 := MockData(mem, 100, "ascending")
+	defer r1.Release()
+	r2 := MockData(mem, 100, "descending")
+	defer r2.Release()
+
+	hashes1, err := HashRecordRows(mem, r1, "a", "c")
+	if err != nil {
+		t.Fatalf("received unexpected error: %s", err)
+	}
+	defer hashes1.Release()
+	hashes2, err := HashRecordRows(mem, r2, "a", "c")
+	if err != nil {
+		t.Fatalf("received unexpected error: %s", err)
+	}
+	defer hashes2.Release()
+
+	for i := 0; i < hashes1.Len(); i++ {
+		if hashes1.Value(i) != hashes2.Value(hashes2.Len()-1-i) {
+			t.Errorf("expected row %d of both records to have the same hash", i)
+		}
+	}
+
+	seededHashes, err := HashRecordRowsWithSeed(mem, r1, DefaultHashSeed+1, "a", "c")
+	if err != nil {
+		t.Fatalf("received unexpected error: %s", err)
+	}
+	defer seededHashes.Release()
+	if seededHashes.Value(0) == hashes1.Value(0) {
+		t.Errorf("expected different seeds to produce different hashes")
+	}
+}
+
+func TestHashRecordRowsErrors(t *testing.T) {
+	mem := memory.NewGoAllocator()
+
+	r1 := MockData(mem, 10, "ascending")
+	defer r1.Release()
+
+	_, err := HashRecordRows(mem, r1, "z")
+	if !errors.Is(err, ErrColumnNotFound) {
+		t.Errorf("expected error %v, got %v", ErrColumnNotFound, err)
+	}
+}
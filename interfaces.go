@@ -25,3 +25,8 @@ type arrayBuilder[T comparable] interface {
 	NewArray() arrow.Array
 	Release()
 }
+
+type binaryValueArray interface {
+	arrow.Array
+	Value(i int) []byte
+}
@@ -0,0 +1,159 @@
+package arrowops
+
+import (
+	"fmt"
+
+	"github.com/alekLukanen/errs"
+	"github.com/apache/arrow/go/v17/arrow"
+	"github.com/apache/arrow/go/v17/arrow/array"
+	"github.com/apache/arrow/go/v17/arrow/memory"
+)
+
+/*
+Tests whether each value of the array is equal to one of the values in the
+value set, which must have the same data type as the array. The result is null
+where the value is null, or where the value is not found and the value set
+contains a null, and false otherwise, in the same way as the ExprIn expression.
+The value set is hashed once so testing against large sets is cheap.
+*/
+func IsIn(mem *memory.GoAllocator, arr, valueSet arrow.Array) (*array.Boolean, error) {
+	set, err := newValueSet(valueSet)
+	if err != nil {
+		return nil, err
+	}
+	return set.isIn(mem, arr)
+}
+
+/*
+Returns the index of the first value in the value set that is equal to each
+value of the array as an INT32. The result is null where the value is null or
+is not in the value set. The value set must have the same data type as the array.
+*/
+func IndexIn(mem *memory.GoAllocator, arr, valueSet arrow.Array) (*array.Int32, error) {
+	set, err := newValueSet(valueSet)
+	if err != nil {
+		return nil, err
+	}
+	indices, err := set.indicesOf(arr)
+	if err != nil {
+		return nil, err
+	}
+
+	b := array.NewInt32Builder(mem)
+	defer b.Release()
+	b.Reserve(arr.Len())
+	for _, idx := range indices {
+		if idx == -1 {
+			b.AppendNull()
+		} else {
+			b.Append(int32(idx))
+		}
+	}
+	return b.NewInt32Array(), nil
+}
+
+/*
+The distinct non-null values of an array indexed by their hash.
+*/
+type valueSet struct {
+	values arrow.Array
+	// the index of the first occurrence of each distinct value, values
+	// with the same hash share a bucket
+	buckets map[uint64][]int
+	hasNull bool
+}
+
+func newValueSet(values arrow.Array) (*valueSet, error) {
+	hashes, err := hashArrayValues(values)
+	if err != nil {
+		return nil, errs.Wrap(err, fmt.Errorf("failed to hash the value set"))
+	}
+
+	set := &valueSet{values: values, buckets: make(map[uint64][]int)}
+	for j := 0; j < values.Len(); j++ {
+		if values.IsNull(j) {
+			set.hasNull = true
+			continue
+		}
+		idx, err := set.find(values, j, hashes[j])
+		if err != nil {
+			return nil, err
+		}
+		if idx == -1 {
+			set.buckets[hashes[j]] = append(set.buckets[hashes[j]], j)
+		}
+	}
+	return set, nil
+}
+
+func (s *valueSet) isIn(mem *memory.GoAllocator, arr arrow.Array) (*array.Boolean, error) {
+	indices, err := s.indicesOf(arr)
+	if err != nil {
+		return nil, err
+	}
+
+	b := array.NewBooleanBuilder(mem)
+	defer b.Release()
+	b.Reserve(arr.Len())
+	for i, idx := range indices {
+		if arr.IsNull(i) || (idx == -1 && s.hasNull) {
+			b.AppendNull()
+		} else {
+			b.Append(idx != -1)
+		}
+	}
+	return b.NewBooleanArray(), nil
+}
+
+/*
+The index in the value set of each value of the array, -1 where the value is
+null or not found.
+*/
+func (s *valueSet) indicesOf(arr arrow.Array) ([]int, error) {
+	if !arrow.TypeEqual(arr.DataType(), s.values.DataType()) {
+		return nil, errs.NewStackError(fmt.Errorf("%w| array is %s and value set is %s", ErrDataTypesNotEqual, arr.DataType(), s.values.DataType()))
+	}
+	hashes, err := hashArrayValues(arr)
+	if err != nil {
+		return nil, errs.Wrap(err, fmt.Errorf("failed to hash the array"))
+	}
+
+	indices := make([]int, arr.Len())
+	for i := range indices {
+		if arr.IsNull(i) {
+			indices[i] = -1
+			continue
+		}
+		indices[i], err = s.find(arr, i, hashes[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+	return indices, nil
+}
+
+func (s *valueSet) find(arr arrow.Array, i int, hash uint64) (int, error) {
+	for _, j := range s.buckets[hash] {
+		compareValue, err := compareArrayValues(arr, s.values, i, j)
+		if err != nil {
+			return -1, err
+		}
+		if compareValue == 0 {
+			return j, nil
+		}
+	}
+	return -1, nil
+}
+
+func hashArrayValues(arr arrow.Array) ([]uint64, error) {
+	hasher, err := newColumnHasher(arr.DataType())
+	if err != nil {
+		return nil, err
+	}
+	hashes := make([]uint64, arr.Len())
+	for i := range hashes {
+		hashes[i] = DefaultHashSeed
+	}
+	hasher(arr, hashes)
+	return hashes, nil
+}
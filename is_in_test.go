@@ -0,0 +1,106 @@
+package arrowops
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/apache/arrow/go/v17/arrow"
+	"github.com/apache/arrow/go/v17/arrow/memory"
+)
+
+func TestIsInAndIndexIn(t *testing.T) {
+
+	mem := memory.NewGoAllocator()
+
+	testCases := []struct {
+		caseName        string
+		dataType        arrow.DataType
+		values          string
+		valueSet        string
+		expectedIsIn    []string
+		expectedIndexIn []string
+	}{
+		{
+			caseName:        "integers",
+			dataType:        arrow.PrimitiveTypes.Int64,
+			values:          `[1, 2, null, 4, 2]`,
+			valueSet:        `[4, 2, 2, 8]`,
+			expectedIsIn:    []string{"false", "true", "(null)", "true", "true"},
+			expectedIndexIn: []string{"(null)", "1", "(null)", "0", "1"},
+		},
+		{
+			caseName:        "value_set_with_null",
+			dataType:        arrow.BinaryTypes.String,
+			values:          `["a", "b", null]`,
+			valueSet:        `[null, "b"]`,
+			expectedIsIn:    []string{"(null)", "true", "(null)"},
+			expectedIndexIn: []string{"(null)", "1", "(null)"},
+		},
+		{
+			caseName:        "floats",
+			dataType:        arrow.PrimitiveTypes.Float32,
+			values:          `[0.5, 1.5]`,
+			valueSet:        `[1.5]`,
+			expectedIsIn:    []string{"false", "true"},
+			expectedIndexIn: []string{"(null)", "0"},
+		},
+		{
+			caseName:        "empty_value_set",
+			dataType:        arrow.FixedWidthTypes.Date32,
+			values:          `["2024-01-01"]`,
+			valueSet:        `[]`,
+			expectedIsIn:    []string{"false"},
+			expectedIndexIn: []string{"(null)"},
+		},
+	}
+
+	for idx, tc := range testCases {
+		t.Run(fmt.Sprintf("case_%d:%s", idx, tc.caseName), func(t *testing.T) {
+			arr := castTestArray(t, mem, tc.dataType, tc.values)
+			defer arr.Release()
+			valueSet := castTestArray(t, mem, tc.dataType, tc.valueSet)
+			defer valueSet.Release()
+
+			mask, err := IsIn(mem, arr, valueSet)
+			if err != nil {
+				t.Fatalf("received unexpected error: %s", err)
+			}
+			defer mask.Release()
+			if actual := arrayValueStrings(mask); !slices.Equal(actual, tc.expectedIsIn) {
+				t.Errorf("expected mask %v, got %v", tc.expectedIsIn, actual)
+			}
+
+			indices, err := IndexIn(mem, arr, valueSet)
+			if err != nil {
+				t.Fatalf("received unexpected error: %s", err)
+			}
+			defer indices.Release()
+			if actual := arrayValueStrings(indices); !slices.Equal(actual, tc.expectedIndexIn) {
+				t.Errorf("expected indices %v, got %v", tc.expectedIndexIn, actual)
+			}
+		})
+	}
+
+}
+
+func TestIsInErrors(t *testing.T) {
+	mem := memory.NewGoAllocator()
+
+	ints := castTestArray(t, mem, arrow.PrimitiveTypes.Int64, `[1]`)
+	defer ints.Release()
+	int32s := castTestArray(t, mem, arrow.PrimitiveTypes.Int32, `[1]`)
+	defer int32s.Release()
+	lists := castTestArray(t, mem, arrow.ListOf(arrow.PrimitiveTypes.Int32), `[[1]]`)
+	defer lists.Release()
+
+	_, err := IsIn(mem, ints, int32s)
+	if !errors.Is(err, ErrDataTypesNotEqual) {
+		t.Errorf("expected error %v, got %v", ErrDataTypesNotEqual, err)
+	}
+	_, err = IndexIn(mem, lists, lists)
+	if !errors.Is(err, ErrUnsupportedDataType) {
+		t.Errorf("expected error %v, got %v", ErrUnsupportedDataType, err)
+	}
+}
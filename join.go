@@ -0,0 +1,206 @@
+package arrowops
+
+import (
+	"fmt"
+
+	"github.com/alekLukanen/errs"
+	"github.com/apache/arrow/go/v17/arrow"
+	"github.com/apache/arrow/go/v17/arrow/array"
+	"github.com/apache/arrow/go/v17/arrow/memory"
+)
+
+type JoinType int
+
+const (
+	// rows with matching keys in both records
+	InnerJoin JoinType = iota
+	// every left row, with null right columns when there is no match
+	LeftJoin
+	// every right row, with null left columns when there is no match
+	RightJoin
+	// every row from both records, with null columns when there is no match
+	FullJoin
+	// the left rows with at least one match, left columns only
+	SemiJoin
+	// the left rows without a match, left columns only
+	AntiJoin
+)
+
+func (t JoinType) String() string {
+	switch t {
+	case InnerJoin:
+		return "inner"
+	case LeftJoin:
+		return "left"
+	case RightJoin:
+		return "right"
+	case FullJoin:
+		return "full"
+	case SemiJoin:
+		return "semi"
+	case AntiJoin:
+		return "anti"
+	default:
+		return fmt.Sprintf("JoinType(%d)", int(t))
+	}
+}
+
+const (
+	DefaultJoinLeftSuffix  = "_left"
+	DefaultJoinRightSuffix = "_right"
+)
+
+/*
+Options used by the joins. Columns with the same name in both records are
+renamed by appending LeftSuffix to the left column and RightSuffix to the
+right column. Empty suffixes default to DefaultJoinLeftSuffix and
+DefaultJoinRightSuffix.
+*/
+type JoinOptions struct {
+	LeftSuffix  string
+	RightSuffix string
+}
+
+/*
+Finds the index of each key column in the left and right schemas.
+*/
+func joinKeyIndices(leftSchema, rightSchema *arrow.Schema, leftKeys, rightKeys []string) ([]int, []int, error) {
+	if len(leftKeys) == 0 || len(rightKeys) == 0 {
+		return nil, nil, errs.NewStackError(ErrColumnNamesRequired)
+	}
+	if len(leftKeys) != len(rightKeys) {
+		return nil, nil, errs.NewStackError(fmt.Errorf(
+			"%w| %d left keys and %d right keys", ErrLengthsNotEqual, len(leftKeys), len(rightKeys),
+		))
+	}
+	leftIdxs, err := hashColumnIndices(leftSchema, leftKeys)
+	if err != nil {
+		return nil, nil, errs.Wrap(err, fmt.Errorf("failed to find left keys %v", leftKeys))
+	}
+	rightIdxs, err := hashColumnIndices(rightSchema, rightKeys)
+	if err != nil {
+		return nil, nil, errs.Wrap(err, fmt.Errorf("failed to find right keys %v", rightKeys))
+	}
+	return leftIdxs, rightIdxs, nil
+}
+
+/*
+Null keys never match any other key, including other null keys.
+*/
+func joinKeyIsNull(record arrow.Record, columnIdxs []int, row int) bool {
+	for _, columnIdx := range columnIdxs {
+		if record.Column(columnIdx).IsNull(row) {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+The rows of the left and right records that make up each output row of a
+join. An invalid index produces null values for that record's columns.
+*/
+type joinIndices struct {
+	left       []uint32
+	leftValid  []bool
+	right      []uint32
+	rightValid []bool
+}
+
+func newJoinIndices() *joinIndices {
+	return &joinIndices{
+		left:       make([]uint32, 0),
+		leftValid:  make([]bool, 0),
+		right:      make([]uint32, 0),
+		rightValid: make([]bool, 0),
+	}
+}
+
+func (j *joinIndices) appendMatch(leftRow, rightRow int) {
+	j.left = append(j.left, uint32(leftRow))
+	j.leftValid = append(j.leftValid, true)
+	j.right = append(j.right, uint32(rightRow))
+	j.rightValid = append(j.rightValid, true)
+}
+
+func (j *joinIndices) appendLeftOnly(leftRow int) {
+	j.left = append(j.left, uint32(leftRow))
+	j.leftValid = append(j.leftValid, true)
+	j.right = append(j.right, 0)
+	j.rightValid = append(j.rightValid, false)
+}
+
+func (j *joinIndices) appendRightOnly(rightRow int) {
+	j.left = append(j.left, 0)
+	j.leftValid = append(j.leftValid, false)
+	j.right = append(j.right, uint32(rightRow))
+	j.rightValid = append(j.rightValid, true)
+}
+
+/*
+Gathers the output rows of a join from the left and right records. Semi and
+anti joins only output the left columns, the other joins output the left
+columns followed by the right columns.
+*/
+func joinOutput(mem *memory.GoAllocator, left, right arrow.Record, joinType JoinType, indices *joinIndices, opts JoinOptions) (arrow.Record, error) {
+	leftIndicesBuilder := array.NewUint32Builder(mem)
+	defer leftIndicesBuilder.Release()
+	leftIndicesBuilder.AppendValues(indices.left, indices.leftValid)
+	leftIndices := leftIndicesBuilder.NewUint32Array()
+	defer leftIndices.Release()
+
+	if joinType == SemiJoin || joinType == AntiJoin {
+		output, err := TakeRecord(mem, left, leftIndices)
+		if err != nil {
+			return nil, errs.Wrap(err, fmt.Errorf("failed to take %d rows from the left record", leftIndices.Len()))
+		}
+		return output, nil
+	}
+
+	rightIndicesBuilder := array.NewUint32Builder(mem)
+	defer rightIndicesBuilder.Release()
+	rightIndicesBuilder.AppendValues(indices.right, indices.rightValid)
+	rightIndices := rightIndicesBuilder.NewUint32Array()
+	defer rightIndices.Release()
+
+	leftSuffix, rightSuffix := opts.LeftSuffix, opts.RightSuffix
+	if leftSuffix == "" {
+		leftSuffix = DefaultJoinLeftSuffix
+	}
+	if rightSuffix == "" {
+		rightSuffix = DefaultJoinRightSuffix
+	}
+
+	fields := make([]arrow.Field, 0, left.NumCols()+right.NumCols())
+	columns := make([]arrow.Array, 0, left.NumCols()+right.NumCols())
+	defer func() {
+		for _, column := range columns {
+			column.Release()
+		}
+	}()
+
+	gather := func(record, other arrow.Record, indices *array.Uint32, suffix string, nullable bool) error {
+		for i, field := range record.Schema().Fields() {
+			column, err := TakeArray(mem, record.Column(i), indices)
+			if err != nil {
+				return errs.Wrap(err, fmt.Errorf("failed to take column %s", field.Name))
+			}
+			columns = append(columns, column)
+
+			if other.Schema().HasField(field.Name) {
+				field.Name += suffix
+			}
+			field.Nullable = field.Nullable || nullable
+			fields = append(fields, field)
+		}
+		return nil
+	}
+	if err := gather(left, right, leftIndices, leftSuffix, joinType == RightJoin || joinType == FullJoin); err != nil {
+		return nil, err
+	}
+	if err := gather(right, left, rightIndices, rightSuffix, joinType == LeftJoin || joinType == FullJoin); err != nil {
+		return nil, err
+	}
+
+	return array.NewRecord(arrow.NewSchema(fields, nil), columns, int64(len(indices.left))), nil
+}
@@ -0,0 +1,135 @@
+package arrowops
+
+import (
+	"fmt"
+
+	"github.com/alekLukanen/errs"
+	"github.com/apache/arrow/go/v17/arrow"
+	"github.com/apache/arrow/go/v17/arrow/memory"
+)
+
+/*
+Joins the left and right records where the values of the key columns are equal,
+using the default join options. Both records must be sorted by the keys.
+*/
+func MergeJoin(mem *memory.GoAllocator, left, right arrow.Record, keys []string, joinType JoinType) (arrow.Record, error) {
+	return MergeJoinWithOptions(mem, left, right, keys, joinType, JoinOptions{})
+}
+
+/*
+Joins the left and right records where the values of the key columns are equal
+by walking both records at the same time. Both records must be sorted in
+ascending order by the keys with null values first, the order produced by
+SortRecordIndices with SortKeysFromColumns, otherwise ErrNotSorted is returned.
+Runs of equal keys on both sides produce every combination of their rows. Key
+columns must have the same data types in both records and rows with a null key
+never match. Output rows are ordered by key.
+*/
+func MergeJoinWithOptions(mem *memory.GoAllocator, left, right arrow.Record, keys []string, joinType JoinType, opts JoinOptions) (arrow.Record, error) {
+	left.Retain()
+	defer left.Release()
+	right.Retain()
+	defer right.Release()
+
+	leftIdxs, rightIdxs, err := joinKeyIndices(left.Schema(), right.Schema(), keys, keys)
+	if err != nil {
+		return nil, err
+	}
+	sortKeys := SortKeysFromColumns(keys...)
+	comparator, err := newRowComparator(left.Schema(), right.Schema(), leftIdxs, rightIdxs, sortKeys)
+	if err != nil {
+		return nil, errs.Wrap(err, fmt.Errorf("failed to create comparator for keys %v", keys))
+	}
+	leftComparator, err := newRowComparator(left.Schema(), left.Schema(), leftIdxs, leftIdxs, sortKeys)
+	if err != nil {
+		return nil, errs.Wrap(err, fmt.Errorf("failed to create comparator for left keys %v", keys))
+	}
+	rightComparator, err := newRowComparator(right.Schema(), right.Schema(), rightIdxs, rightIdxs, sortKeys)
+	if err != nil {
+		return nil, errs.Wrap(err, fmt.Errorf("failed to create comparator for right keys %v", keys))
+	}
+	if err := checkRecordSorted(left, leftComparator); err != nil {
+		return nil, errs.Wrap(err, fmt.Errorf("left record is not sorted by keys %v", keys))
+	}
+	if err := checkRecordSorted(right, rightComparator); err != nil {
+		return nil, errs.Wrap(err, fmt.Errorf("right record is not sorted by keys %v", keys))
+	}
+
+	indices := newJoinIndices()
+	leftUnmatched := func(row int) {
+		if joinType == LeftJoin || joinType == FullJoin || joinType == AntiJoin {
+			indices.appendLeftOnly(row)
+		}
+	}
+	rightUnmatched := func(row int) {
+		if joinType == RightJoin || joinType == FullJoin {
+			indices.appendRightOnly(row)
+		}
+	}
+
+	numLeftRows, numRightRows := int(left.NumRows()), int(right.NumRows())
+	i, j := 0, 0
+	for i < numLeftRows && j < numRightRows {
+		if joinKeyIsNull(left, leftIdxs, i) {
+			leftUnmatched(i)
+			i++
+			continue
+		}
+		if joinKeyIsNull(right, rightIdxs, j) {
+			rightUnmatched(j)
+			j++
+			continue
+		}
+
+		compareValue := comparator.Compare(left, right, i, j)
+		if compareValue < 0 {
+			leftUnmatched(i)
+			i++
+			continue
+		} else if compareValue > 0 {
+			rightUnmatched(j)
+			j++
+			continue
+		}
+
+		// find the runs of rows with the same key on both sides
+		leftEnd := i + 1
+		for leftEnd < numLeftRows && leftComparator.Equal(left, left, i, leftEnd) {
+			leftEnd++
+		}
+		rightEnd := j + 1
+		for rightEnd < numRightRows && rightComparator.Equal(right, right, j, rightEnd) {
+			rightEnd++
+		}
+
+		for leftRow := i; leftRow < leftEnd; leftRow++ {
+			switch joinType {
+			case SemiJoin:
+				indices.appendLeftOnly(leftRow)
+			case AntiJoin:
+			default:
+				for rightRow := j; rightRow < rightEnd; rightRow++ {
+					indices.appendMatch(leftRow, rightRow)
+				}
+			}
+		}
+		i, j = leftEnd, rightEnd
+	}
+	for ; i < numLeftRows; i++ {
+		leftUnmatched(i)
+	}
+	for ; j < numRightRows; j++ {
+		rightUnmatched(j)
+	}
+
+	return joinOutput(mem, left, right, joinType, indices, opts)
+}
+
+func checkRecordSorted(record arrow.Record, comparator *RowComparator) error {
+	for i := 1; i < int(record.NumRows()); i++ {
+		if comparator.Compare(record, record, i-1, i) > 0 {
+			return errs.NewStackError(fmt.Errorf("%w| row %d is greater than row %d", ErrNotSorted, i-1, i))
+		}
+	}
+	return nil
+}
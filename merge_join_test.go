@@ -0,0 +1,147 @@
+package arrowops
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/apache/arrow/go/v17/arrow"
+	"github.com/apache/arrow/go/v17/arrow/memory"
+)
+
+func BenchmarkMergeJoin(b *testing.B) {
+	for _, size := range TEST_SIZES {
+		b.Run(fmt.Sprintf("size=%d", size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				mem := memory.NewGoAllocator()
+				r1 := MockData(mem, size, "ascending")
+				defer r1.Release()
+				r2 := MockData(mem, size, "ascending")
+				defer r2.Release()
+				b.StartTimer()
+
+				joined, err := MergeJoin(mem, r1, r2, []string{"a"}, InnerJoin)
+				if err != nil {
+					b.Fatalf("received error while joining records '%s'", err)
+				}
+				joined.Release()
+			}
+		})
+	}
+}
+
+func TestMergeJoin(t *testing.T) {
+
+	mem := memory.NewGoAllocator()
+
+	schema := arrow.NewSchema(
+		[]arrow.Field{
+			{Name: "id", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+			{Name: "name", Type: arrow.BinaryTypes.String},
+		}, nil)
+
+	left := recordFromJSON(t, mem, schema, `[{"id": null, "name": "e"}, {"id": 1, "name": "a"}, {"id": 2, "name": "b"}, {"id": 2, "name": "c"}, {"id": 3, "name": "d"}]`)
+	defer left.Release()
+	right := recordFromJSON(t, mem, schema, `[{"id": null, "name": "v"}, {"id": 2, "name": "x"}, {"id": 3, "name": "y"}, {"id": 3, "name": "z"}, {"id": 4, "name": "w"}]`)
+	defer right.Release()
+
+	testCases := []struct {
+		joinType     JoinType
+		expectedRows []string
+	}{
+		{
+			joinType:     InnerJoin,
+			expectedRows: []string{"2,b,2,x", "2,c,2,x", "3,d,3,y", "3,d,3,z"},
+		},
+		{
+			joinType: LeftJoin,
+			expectedRows: []string{
+				"(null),e,(null),(null)", "1,a,(null),(null)", "2,b,2,x", "2,c,2,x", "3,d,3,y", "3,d,3,z",
+			},
+		},
+		{
+			joinType: RightJoin,
+			expectedRows: []string{
+				"(null),(null),(null),v", "2,b,2,x", "2,c,2,x", "3,d,3,y", "3,d,3,z", "(null),(null),4,w",
+			},
+		},
+		{
+			joinType: FullJoin,
+			expectedRows: []string{
+				"(null),e,(null),(null)", "(null),(null),(null),v", "1,a,(null),(null)",
+				"2,b,2,x", "2,c,2,x", "3,d,3,y", "3,d,3,z", "(null),(null),4,w",
+			},
+		},
+		{
+			joinType:     SemiJoin,
+			expectedRows: []string{"2,b", "2,c", "3,d"},
+		},
+		{
+			joinType:     AntiJoin,
+			expectedRows: []string{"(null),e", "1,a"},
+		},
+	}
+
+	for idx, tc := range testCases {
+		t.Run(fmt.Sprintf("case_%d:%s", idx, tc.joinType), func(t *testing.T) {
+			joined, err := MergeJoin(mem, left, right, []string{"id"}, tc.joinType)
+			if err != nil {
+				t.Fatalf("received unexpected error: %s", err)
+			}
+			defer joined.Release()
+
+			if actual := recordRowStrings(joined); !slices.Equal(actual, tc.expectedRows) {
+				t.Errorf("expected rows %v, got %v", tc.expectedRows, actual)
+			}
+		})
+	}
+
+}
+
+func TestMergeJoinDuplicateKeysOnBothSides(t *testing.T) {
+	mem := memory.NewGoAllocator()
+
+	schema := arrow.NewSchema(
+		[]arrow.Field{
+			{Name: "id", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+			{Name: "name", Type: arrow.BinaryTypes.String},
+		}, nil)
+
+	left := recordFromJSON(t, mem, schema, `[{"id": 1, "name": "a"}, {"id": 1, "name": "b"}, {"id": 2, "name": "c"}]`)
+	defer left.Release()
+	right := recordFromJSON(t, mem, schema, `[{"id": 1, "name": "x"}, {"id": 1, "name": "y"}, {"id": 2, "name": "z"}]`)
+	defer right.Release()
+
+	joined, err := MergeJoin(mem, left, right, []string{"id"}, InnerJoin)
+	if err != nil {
+		t.Fatalf("received unexpected error: %s", err)
+	}
+	defer joined.Release()
+
+	expectedRows := []string{"1,a,1,x", "1,a,1,y", "1,b,1,x", "1,b,1,y", "2,c,2,z"}
+	if actual := recordRowStrings(joined); !slices.Equal(actual, expectedRows) {
+		t.Errorf("expected rows %v, got %v", expectedRows, actual)
+	}
+}
+
+func TestMergeJoinNotSorted(t *testing.T) {
+	mem := memory.NewGoAllocator()
+
+	schema := arrow.NewSchema(
+		[]arrow.Field{
+			{Name: "id", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+			{Name: "name", Type: arrow.BinaryTypes.String},
+		}, nil)
+
+	left := recordFromJSON(t, mem, schema, `[{"id": 2, "name": "a"}, {"id": 1, "name": "b"}]`)
+	defer left.Release()
+	right := recordFromJSON(t, mem, schema, `[{"id": 1, "name": "x"}, {"id": 2, "name": "y"}]`)
+	defer right.Release()
+
+	_, err := MergeJoin(mem, left, right, []string{"id"}, InnerJoin)
+	if !errors.Is(err, ErrNotSorted) {
+		t.Errorf("expected error %v, got %v", ErrNotSorted, err)
+	}
+}
@@ -0,0 +1,56 @@
+package arrowops
+
+import (
+	"fmt"
+
+	"github.com/alekLukanen/errs"
+	"github.com/apache/arrow/go/v17/arrow"
+	"github.com/apache/arrow/go/v17/arrow/memory"
+)
+
+/*
+Splits the record into n records by the hash of the key columns, computed in the
+same way as HashRecordRows, so rows with equal keys always go to the same
+partition, including across calls and records. Each partition keeps the rows in
+the order they appear in the record and may be empty. The caller must release
+each of the returned records.
+*/
+func PartitionRecord(mem *memory.GoAllocator, record arrow.Record, keys []string, n int) ([]arrow.Record, error) {
+	record.Retain()
+	defer record.Release()
+
+	if len(keys) == 0 {
+		return nil, errs.NewStackError(ErrColumnNamesRequired)
+	}
+	if n <= 0 {
+		return nil, errs.NewStackError(fmt.Errorf("%w| expected at least one partition but got %d", ErrInvalidPartitions, n))
+	}
+
+	columnIdxs, err := hashColumnIndices(record.Schema(), keys)
+	if err != nil {
+		return nil, err
+	}
+	hashes, err := hashRecordRows(record, columnIdxs, DefaultHashSeed)
+	if err != nil {
+		return nil, errs.Wrap(err, fmt.Errorf("failed to hash columns %v", keys))
+	}
+
+	rows := make([][]uint32, n)
+	for i, hash := range hashes {
+		partition := hash % uint64(n)
+		rows[partition] = append(rows[partition], uint32(i))
+	}
+
+	partitions := make([]arrow.Record, 0, n)
+	for idx, partitionRows := range rows {
+		partition, err := takeRecordRows(mem, record, partitionRows)
+		if err != nil {
+			for _, p := range partitions {
+				p.Release()
+			}
+			return nil, errs.Wrap(err, fmt.Errorf("failed to take rows for partition %d", idx))
+		}
+		partitions = append(partitions, partition)
+	}
+	return partitions, nil
+}
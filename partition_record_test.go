@@ -0,0 +1,110 @@
+package arrowops
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/apache/arrow/go/v17/arrow"
+	"github.com/apache/arrow/go/v17/arrow/array"
+	"github.com/apache/arrow/go/v17/arrow/memory"
+)
+
+func TestPartitionRecord(t *testing.T) {
+	mem := memory.NewGoAllocator()
+
+	record := recordFromJSON(t, mem, arrow.NewSchema(
+		[]arrow.Field{
+			{Name: "dept", Type: arrow.BinaryTypes.String},
+			{Name: "salary", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+			{Name: "id", Type: arrow.PrimitiveTypes.Int32},
+		}, nil), `[
+			{"dept": "a", "salary": 10, "id": 0},
+			{"dept": "b", "salary": 20, "id": 1},
+			{"dept": "a", "salary": 30, "id": 2},
+			{"dept": "a", "salary": 10, "id": 3},
+			{"dept": "b", "salary": null, "id": 4},
+			{"dept": "a", "salary": 40, "id": 5}
+		]`,
+	)
+	defer record.Release()
+
+	partitions, err := PartitionRecord(mem, record, []string{"dept"}, 4)
+	if err != nil {
+		t.Fatalf("received unexpected error: %s", err)
+	}
+	defer func() {
+		for _, partition := range partitions {
+			partition.Release()
+		}
+	}()
+	if len(partitions) != 4 {
+		t.Fatalf("expected 4 partitions, got %d", len(partitions))
+	}
+
+	// every row is in exactly one partition and each department is in a single partition
+	numRows := 0
+	deptPartitions := make(map[string]int)
+	for idx, partition := range partitions {
+		if !partition.Schema().Equal(record.Schema()) {
+			t.Errorf("expected schema %s, got %s", record.Schema(), partition.Schema())
+		}
+		numRows += int(partition.NumRows())
+		for _, dept := range arrayValueStrings(partition.Column(0)) {
+			if other, ok := deptPartitions[dept]; ok && other != idx {
+				t.Errorf("department %s is in partitions %d and %d", dept, other, idx)
+			}
+			deptPartitions[dept] = idx
+		}
+	}
+	if numRows != int(record.NumRows()) {
+		t.Errorf("expected %d rows across the partitions, got %d", record.NumRows(), numRows)
+	}
+
+	// rows keep their order within a partition
+	for idx, partition := range partitions {
+		ids := partition.Column(2).(*array.Int32).Int32Values()
+		if !slices.IsSorted(ids) {
+			t.Errorf("expected the ids of partition %d to be in order, got %v", idx, ids)
+		}
+	}
+
+	// the same key goes to the same partition in another record
+	slice := record.NewSlice(1, 2)
+	defer slice.Release()
+	slicePartitions, err := PartitionRecord(mem, slice, []string{"dept"}, 4)
+	if err != nil {
+		t.Fatalf("received unexpected error: %s", err)
+	}
+	defer func() {
+		for _, partition := range slicePartitions {
+			partition.Release()
+		}
+	}()
+	if slicePartitions[deptPartitions["b"]].NumRows() != 1 {
+		t.Errorf("expected department b in partition %d", deptPartitions["b"])
+	}
+}
+
+func TestPartitionRecordErrors(t *testing.T) {
+	mem := memory.NewGoAllocator()
+
+	record := recordFromJSON(t, mem, arrow.NewSchema(
+		[]arrow.Field{{Name: "dept", Type: arrow.BinaryTypes.String}}, nil),
+		`[{"dept": "a"}, {"dept": "b"}]`,
+	)
+	defer record.Release()
+
+	_, err := PartitionRecord(mem, record, []string{"dept"}, 0)
+	if !errors.Is(err, ErrInvalidPartitions) {
+		t.Errorf("expected error %v, got %v", ErrInvalidPartitions, err)
+	}
+	_, err = PartitionRecord(mem, record, nil, 2)
+	if !errors.Is(err, ErrColumnNamesRequired) {
+		t.Errorf("expected error %v, got %v", ErrColumnNamesRequired, err)
+	}
+	_, err = PartitionRecord(mem, record, []string{"missing"}, 2)
+	if !errors.Is(err, ErrColumnNotFound) {
+		t.Errorf("expected error %v, got %v", ErrColumnNotFound, err)
+	}
+}
@@ -0,0 +1,206 @@
+package arrowops
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/alekLukanen/errs"
+	"github.com/apache/arrow/go/v17/arrow"
+	"github.com/apache/arrow/go/v17/arrow/array"
+)
+
+/*
+Options used by DiffRecords. When Fields is empty every column in either
+record is compared. MaxValueDiffs limits the number of differing values
+stored for each column, all differences are still counted. A value of zero
+stores every difference.
+*/
+type DiffOptions struct {
+	Fields            []string
+	MaxValueDiffs     int
+	IgnoreColumnOrder bool
+}
+
+/*
+A single value that differs between the two records. The values are
+formatted with arrow.Array.ValueStr.
+*/
+type ValueDiff struct {
+	Row    int
+	Value1 string
+	Value2 string
+}
+
+/*
+The differences found in a single column. NumDiffs is the total number of
+rows that differ, which can be larger than len(Diffs) when
+DiffOptions.MaxValueDiffs is set.
+*/
+type ColumnDiff struct {
+	Column   string
+	NumDiffs int
+	Diffs    []ValueDiff
+}
+
+/*
+The result of comparing two records with DiffRecords. Columns are matched
+by name so records with the same columns in a different order can still
+be compared value by value.
+*/
+type RecordDiff struct {
+	SchemaDiffs []string
+	NumRows1    int64
+	NumRows2    int64
+	ColumnDiffs []ColumnDiff
+}
+
+/*
+Returns true when no differences were found.
+*/
+func (d *RecordDiff) Equal() bool {
+	return len(d.SchemaDiffs) == 0 && d.NumRows1 == d.NumRows2 && len(d.ColumnDiffs) == 0
+}
+
+/*
+Formats the differences in a form meant to be read in test failures.
+*/
+func (d *RecordDiff) String() string {
+	if d.Equal() {
+		return "records are equal"
+	}
+
+	var sb strings.Builder
+	for _, schemaDiff := range d.SchemaDiffs {
+		sb.WriteString(fmt.Sprintf("schema: %s\n", schemaDiff))
+	}
+	if d.NumRows1 != d.NumRows2 {
+		sb.WriteString(fmt.Sprintf("rows: record1 has %d rows, record2 has %d rows\n", d.NumRows1, d.NumRows2))
+	}
+	for _, columnDiff := range d.ColumnDiffs {
+		sb.WriteString(fmt.Sprintf("column %s: %d rows differ\n", columnDiff.Column, columnDiff.NumDiffs))
+		for _, valueDiff := range columnDiff.Diffs {
+			sb.WriteString(fmt.Sprintf("  row %d: %s != %s\n", valueDiff.Row, valueDiff.Value1, valueDiff.Value2))
+		}
+		if hidden := columnDiff.NumDiffs - len(columnDiff.Diffs); hidden > 0 {
+			sb.WriteString(fmt.Sprintf("  ... %d more\n", hidden))
+		}
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+/*
+Compares the two records and reports every difference found. Columns are
+matched by name. Columns missing from either record or with different
+data types are reported as schema differences and their values are not
+compared. When the records have a different number of rows only the rows
+present in both records are compared.
+*/
+func DiffRecords(rec1, rec2 arrow.Record, opts DiffOptions) (*RecordDiff, error) {
+	rec1.Retain()
+	defer rec1.Release()
+	rec2.Retain()
+	defer rec2.Release()
+
+	diff := &RecordDiff{
+		SchemaDiffs: make([]string, 0),
+		NumRows1:    rec1.NumRows(),
+		NumRows2:    rec2.NumRows(),
+		ColumnDiffs: make([]ColumnDiff, 0),
+	}
+
+	fields := opts.Fields
+	if len(fields) == 0 {
+		fields = diffColumnNames(rec1.Schema(), rec2.Schema())
+	}
+
+	numRows := min(rec1.NumRows(), rec2.NumRows())
+	for _, field := range fields {
+		column1Idxs := rec1.Schema().FieldIndices(field)
+		column2Idxs := rec2.Schema().FieldIndices(field)
+		if len(column1Idxs) == 0 && len(column2Idxs) == 0 {
+			return nil, errs.NewStackError(fmt.Errorf("%w| column name: %s", ErrColumnNotFound, field))
+		} else if len(column1Idxs) == 0 {
+			diff.SchemaDiffs = append(diff.SchemaDiffs, fmt.Sprintf("column %s missing from record1", field))
+			continue
+		} else if len(column2Idxs) == 0 {
+			diff.SchemaDiffs = append(diff.SchemaDiffs, fmt.Sprintf("column %s missing from record2", field))
+			continue
+		}
+
+		column1Idx := column1Idxs[0]
+		column2Idx := column2Idxs[0]
+		if !opts.IgnoreColumnOrder && column1Idx != column2Idx {
+			diff.SchemaDiffs = append(diff.SchemaDiffs, fmt.Sprintf(
+				"column %s is at position %d in record1 and %d in record2", field, column1Idx, column2Idx,
+			))
+		}
+
+		column1 := rec1.Column(column1Idx)
+		column2 := rec2.Column(column2Idx)
+		if !arrow.TypeEqual(column1.DataType(), column2.DataType()) {
+			diff.SchemaDiffs = append(diff.SchemaDiffs, fmt.Sprintf(
+				"column %s has type %s in record1 and %s in record2", field, column1.DataType(), column2.DataType(),
+			))
+			continue
+		}
+
+		columnDiff, err := diffArrays(field, column1, column2, int(numRows), opts.MaxValueDiffs)
+		if err != nil {
+			return nil, errs.Wrap(err, fmt.Errorf("failed to diff column %s", field))
+		}
+		if columnDiff.NumDiffs > 0 {
+			diff.ColumnDiffs = append(diff.ColumnDiffs, columnDiff)
+		}
+	}
+
+	return diff, nil
+}
+
+func diffArrays(column string, a1, a2 arrow.Array, numRows int, maxValueDiffs int) (ColumnDiff, error) {
+	columnDiff := ColumnDiff{Column: column, Diffs: make([]ValueDiff, 0)}
+
+	// fall back to the slower slice comparison for types compareArrayValues does not support
+	_, err := newArrayValueComparator(a1.DataType())
+	useSliceEqual := err != nil
+
+	for i := 0; i < numRows; i++ {
+		var equal bool
+		if useSliceEqual {
+			equal = array.SliceEqual(a1, int64(i), int64(i+1), a2, int64(i), int64(i+1))
+		} else {
+			cmpValue, err := compareArrayValues(a1, a2, i, i)
+			if err != nil {
+				return columnDiff, err
+			}
+			equal = cmpValue == 0
+		}
+		if equal {
+			continue
+		}
+
+		columnDiff.NumDiffs++
+		if maxValueDiffs <= 0 || len(columnDiff.Diffs) < maxValueDiffs {
+			columnDiff.Diffs = append(columnDiff.Diffs, ValueDiff{Row: i, Value1: a1.ValueStr(i), Value2: a2.ValueStr(i)})
+		}
+	}
+	return columnDiff, nil
+}
+
+/*
+Returns the names of the columns in schema1 followed by the
+names of the columns only found in schema2.
+*/
+func diffColumnNames(schema1, schema2 *arrow.Schema) []string {
+	names := make([]string, 0, schema1.NumFields())
+	seen := make(map[string]struct{})
+	for _, schema := range []*arrow.Schema{schema1, schema2} {
+		for _, field := range schema.Fields() {
+			if _, ok := seen[field.Name]; ok {
+				continue
+			}
+			seen[field.Name] = struct{}{}
+			names = append(names, field.Name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,208 @@
+package arrowops
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/apache/arrow/go/v17/arrow"
+	"github.com/apache/arrow/go/v17/arrow/array"
+	"github.com/apache/arrow/go/v17/arrow/memory"
+)
+
+func TestDiffRecords(t *testing.T) {
+
+	mem := memory.NewGoAllocator()
+
+	baseRecordBldr := func() arrow.Record {
+		recBuilder := array.NewRecordBuilder(
+			mem, arrow.NewSchema(
+				[]arrow.Field{
+					{Name: "a", Type: arrow.PrimitiveTypes.Int64},
+					{Name: "b", Type: arrow.BinaryTypes.String},
+					{Name: "c", Type: arrow.PrimitiveTypes.Float64},
+				}, nil),
+		)
+		defer recBuilder.Release()
+
+		recBuilder.Field(0).(*array.Int64Builder).AppendValues([]int64{1, 2, 3}, nil)
+		recBuilder.Field(1).(*array.StringBuilder).AppendValues([]string{"a", "b", "c"}, nil)
+		recBuilder.Field(2).(*array.Float64Builder).AppendValues([]float64{1.1, 2.2, 3.3}, nil)
+		return recBuilder.NewRecord()
+	}
+
+	testCases := []struct {
+		caseName            string
+		record1             func() arrow.Record
+		record2             func() arrow.Record
+		opts                DiffOptions
+		expectedEqual       bool
+		expectedSchemaDiffs int
+		expectedColumnDiffs []ColumnDiff
+		expectedErr         error
+	}{
+		{
+			caseName:            "equal_records",
+			record1:             baseRecordBldr,
+			record2:             baseRecordBldr,
+			expectedEqual:       true,
+			expectedColumnDiffs: []ColumnDiff{},
+		},
+		{
+			caseName: "different_values",
+			record1:  baseRecordBldr,
+			record2: func() arrow.Record {
+				recBuilder := array.NewRecordBuilder(
+					mem, arrow.NewSchema(
+						[]arrow.Field{
+							{Name: "a", Type: arrow.PrimitiveTypes.Int64},
+							{Name: "b", Type: arrow.BinaryTypes.String},
+							{Name: "c", Type: arrow.PrimitiveTypes.Float64},
+						}, nil),
+				)
+				defer recBuilder.Release()
+
+				recBuilder.Field(0).(*array.Int64Builder).AppendValues([]int64{1, 7, 3}, nil)
+				recBuilder.Field(1).(*array.StringBuilder).AppendValues([]string{"a", "b", "x"}, []bool{true, false, true})
+				recBuilder.Field(2).(*array.Float64Builder).AppendValues([]float64{1.1, 2.2, 3.3}, nil)
+				return recBuilder.NewRecord()
+			},
+			expectedEqual: false,
+			expectedColumnDiffs: []ColumnDiff{
+				{Column: "a", NumDiffs: 1, Diffs: []ValueDiff{{Row: 1, Value1: "2", Value2: "7"}}},
+				{Column: "b", NumDiffs: 2, Diffs: []ValueDiff{{Row: 1, Value1: "b", Value2: "(null)"}, {Row: 2, Value1: "c", Value2: "x"}}},
+			},
+		},
+		{
+			caseName: "max_value_diffs",
+			record1:  baseRecordBldr,
+			record2: func() arrow.Record {
+				recBuilder := array.NewRecordBuilder(
+					mem, arrow.NewSchema(
+						[]arrow.Field{
+							{Name: "a", Type: arrow.PrimitiveTypes.Int64},
+							{Name: "b", Type: arrow.BinaryTypes.String},
+							{Name: "c", Type: arrow.PrimitiveTypes.Float64},
+						}, nil),
+				)
+				defer recBuilder.Release()
+
+				recBuilder.Field(0).(*array.Int64Builder).AppendValues([]int64{4, 5, 6}, nil)
+				recBuilder.Field(1).(*array.StringBuilder).AppendValues([]string{"a", "b", "c"}, nil)
+				recBuilder.Field(2).(*array.Float64Builder).AppendValues([]float64{1.1, 2.2, 3.3}, nil)
+				return recBuilder.NewRecord()
+			},
+			opts:          DiffOptions{MaxValueDiffs: 1},
+			expectedEqual: false,
+			expectedColumnDiffs: []ColumnDiff{
+				{Column: "a", NumDiffs: 3, Diffs: []ValueDiff{{Row: 0, Value1: "1", Value2: "4"}}},
+			},
+		},
+		{
+			caseName: "columns_in_different_order",
+			record1:  baseRecordBldr,
+			record2: func() arrow.Record {
+				recBuilder := array.NewRecordBuilder(
+					mem, arrow.NewSchema(
+						[]arrow.Field{
+							{Name: "c", Type: arrow.PrimitiveTypes.Float64},
+							{Name: "b", Type: arrow.BinaryTypes.String},
+							{Name: "a", Type: arrow.PrimitiveTypes.Int64},
+						}, nil),
+				)
+				defer recBuilder.Release()
+
+				recBuilder.Field(0).(*array.Float64Builder).AppendValues([]float64{1.1, 2.2, 3.3}, nil)
+				recBuilder.Field(1).(*array.StringBuilder).AppendValues([]string{"a", "b", "c"}, nil)
+				recBuilder.Field(2).(*array.Int64Builder).AppendValues([]int64{1, 2, 3}, nil)
+				return recBuilder.NewRecord()
+			},
+			opts:                DiffOptions{IgnoreColumnOrder: true},
+			expectedEqual:       true,
+			expectedColumnDiffs: []ColumnDiff{},
+		},
+		{
+			caseName: "schema_and_row_count_differences",
+			record1:  baseRecordBldr,
+			record2: func() arrow.Record {
+				recBuilder := array.NewRecordBuilder(
+					mem, arrow.NewSchema(
+						[]arrow.Field{
+							{Name: "a", Type: arrow.PrimitiveTypes.Int32},
+							{Name: "b", Type: arrow.BinaryTypes.String},
+							{Name: "d", Type: arrow.PrimitiveTypes.Float64},
+						}, nil),
+				)
+				defer recBuilder.Release()
+
+				recBuilder.Field(0).(*array.Int32Builder).AppendValues([]int32{1, 2}, nil)
+				recBuilder.Field(1).(*array.StringBuilder).AppendValues([]string{"a", "z"}, nil)
+				recBuilder.Field(2).(*array.Float64Builder).AppendValues([]float64{1.1, 2.2}, nil)
+				return recBuilder.NewRecord()
+			},
+			expectedEqual:       false,
+			expectedSchemaDiffs: 3,
+			expectedColumnDiffs: []ColumnDiff{
+				{Column: "b", NumDiffs: 1, Diffs: []ValueDiff{{Row: 1, Value1: "b", Value2: "z"}}},
+			},
+		},
+		{
+			caseName:    "unknown_field",
+			record1:     baseRecordBldr,
+			record2:     baseRecordBldr,
+			opts:        DiffOptions{Fields: []string{"z"}},
+			expectedErr: ErrColumnNotFound,
+		},
+	}
+
+	for idx, tc := range testCases {
+		t.Run(fmt.Sprintf("case_%d:%s", idx, tc.caseName), func(t *testing.T) {
+			record1 := tc.record1()
+			record2 := tc.record2()
+			defer record1.Release()
+			defer record2.Release()
+
+			diff, err := DiffRecords(record1, record2, tc.opts)
+			if !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("expected error: %v, got: %v", tc.expectedErr, err)
+			}
+			if err != nil {
+				return
+			}
+
+			if diff.Equal() != tc.expectedEqual {
+				t.Errorf("expected equal to be %t, got diff:\n%s", tc.expectedEqual, diff)
+			}
+			if len(diff.SchemaDiffs) != tc.expectedSchemaDiffs {
+				t.Errorf("expected %d schema diffs, got %v", tc.expectedSchemaDiffs, diff.SchemaDiffs)
+			}
+			if fmt.Sprint(diff.ColumnDiffs) != fmt.Sprint(tc.expectedColumnDiffs) {
+				t.Errorf("expected column diffs %v, got %v", tc.expectedColumnDiffs, diff.ColumnDiffs)
+			}
+		})
+	}
+
+}
+
+func TestRecordDiffString(t *testing.T) {
+	diff := &RecordDiff{
+		SchemaDiffs: []string{"column d missing from record1"},
+		NumRows1:    3,
+		NumRows2:    2,
+		ColumnDiffs: []ColumnDiff{
+			{Column: "a", NumDiffs: 2, Diffs: []ValueDiff{{Row: 1, Value1: "2", Value2: "7"}}},
+		},
+	}
+
+	expected := strings.Join([]string{
+		"schema: column d missing from record1",
+		"rows: record1 has 3 rows, record2 has 2 rows",
+		"column a: 2 rows differ",
+		"  row 1: 2 != 7",
+		"  ... 1 more",
+	}, "\n")
+	if diff.String() != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, diff.String())
+	}
+}
@@ -0,0 +1,221 @@
+package arrowops
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+
+	"github.com/alekLukanen/errs"
+	"github.com/apache/arrow/go/v17/arrow"
+	"github.com/apache/arrow/go/v17/arrow/array"
+)
+
+/*
+A single column used when comparing rows. By default values are compared
+in ascending order and null values are placed before all other values,
+which matches the ordering used by CompareRecordRows.
+*/
+type SortKey struct {
+	Column     string
+	Descending bool
+	NullsLast  bool
+}
+
+/*
+Creates ascending sort keys, with nulls first, for each of the column names.
+*/
+func SortKeysFromColumns(columns ...string) []SortKey {
+	keys := make([]SortKey, len(columns))
+	for i, column := range columns {
+		keys[i] = SortKey{Column: column}
+	}
+	return keys
+}
+
+type arrayValueComparator func(a1, a2 arrow.Array, i1, i2 int) int
+
+type columnComparator struct {
+	column1Idx int
+	column2Idx int
+	compare    arrayValueComparator
+	descending bool
+	nullsLast  bool
+}
+
+/*
+Compares rows from records using a fixed set of key columns. The columns
+and the type specific comparison functions are resolved once when the
+comparator is created so each call to Compare only needs to compare the
+values. Records passed to Compare must have the same schemas as the ones
+the comparator was created with.
+*/
+type RowComparator struct {
+	columns []columnComparator
+}
+
+/*
+Creates a comparator for rows of records with the schemas provided. Each key
+column must exist in both schemas and must have the same data type in both.
+When a column name is used more than once in a schema the first column with
+that name is used.
+*/
+func NewRowComparator(record1Schema, record2Schema *arrow.Schema, keys []SortKey) (*RowComparator, error) {
+	if len(keys) == 0 {
+		return nil, errs.NewStackError(ErrColumnNamesRequired)
+	}
+
+	column1Idxs := make([]int, len(keys))
+	column2Idxs := make([]int, len(keys))
+	for idx, key := range keys {
+		record1Idxs := record1Schema.FieldIndices(key.Column)
+		if len(record1Idxs) == 0 {
+			return nil, errs.NewStackError(fmt.Errorf("%w| column %s not found in record1 schema", ErrColumnNotFound, key.Column))
+		}
+		record2Idxs := record2Schema.FieldIndices(key.Column)
+		if len(record2Idxs) == 0 {
+			return nil, errs.NewStackError(fmt.Errorf("%w| column %s not found in record2 schema", ErrColumnNotFound, key.Column))
+		}
+		column1Idxs[idx] = record1Idxs[0]
+		column2Idxs[idx] = record2Idxs[0]
+	}
+
+	return newRowComparator(record1Schema, record2Schema, column1Idxs, column2Idxs, keys)
+}
+
+func newRowComparator(record1Schema, record2Schema *arrow.Schema, column1Idxs, column2Idxs []int, keys []SortKey) (*RowComparator, error) {
+	columns := make([]columnComparator, len(keys))
+	for idx, key := range keys {
+		field1 := record1Schema.Field(column1Idxs[idx])
+		field2 := record2Schema.Field(column2Idxs[idx])
+		if !arrow.TypeEqual(field1.Type, field2.Type) {
+			return nil, errs.NewStackError(fmt.Errorf(
+				"%w| column %s has type %s in record1 and %s in record2",
+				ErrDataTypesNotEqual,
+				key.Column,
+				field1.Type,
+				field2.Type,
+			))
+		}
+		compare, err := newArrayValueComparator(field1.Type)
+		if err != nil {
+			return nil, errs.Wrap(err, fmt.Errorf("failed to create comparator for column %s", key.Column))
+		}
+		columns[idx] = columnComparator{
+			column1Idx: column1Idxs[idx],
+			column2Idx: column2Idxs[idx],
+			compare:    compare,
+			descending: key.Descending,
+			nullsLast:  key.NullsLast,
+		}
+	}
+	return &RowComparator{columns: columns}, nil
+}
+
+/*
+Determines if the row at index1 in record1 is less/equal/greater than
+the row at index2 in record2. Less than is -1, equal to is 0 and greater than is 1.
+The indices are not bounds checked.
+*/
+func (c *RowComparator) Compare(record1, record2 arrow.Record, index1, index2 int) int {
+	for _, column := range c.columns {
+		a1 := record1.Column(column.column1Idx)
+		a2 := record2.Column(column.column2Idx)
+
+		a1Null := a1.IsNull(index1)
+		a2Null := a2.IsNull(index2)
+		if a1Null || a2Null {
+			if a1Null && a2Null {
+				continue
+			}
+			nullCmp := 1
+			if a1Null {
+				nullCmp = -1
+			}
+			if column.nullsLast {
+				nullCmp = -nullCmp
+			}
+			return nullCmp
+		}
+
+		cmpValue := column.compare(a1, a2, index1, index2)
+		if cmpValue != 0 {
+			if column.descending {
+				return -cmpValue
+			}
+			return cmpValue
+		}
+	}
+	return 0
+}
+
+/*
+Returns true when both rows have equal values, or are both null, for every key column.
+*/
+func (c *RowComparator) Equal(record1, record2 arrow.Record, index1, index2 int) bool {
+	return c.Compare(record1, record2, index1, index2) == 0
+}
+
+func newArrayValueComparator(dataType arrow.DataType) (arrayValueComparator, error) {
+	switch dataType.ID() {
+	case arrow.BOOL:
+		return func(a1, a2 arrow.Array, i1, i2 int) int {
+			return booleanArrayValuesEqual(a1.(*array.Boolean), a2.(*array.Boolean), i1, i2)
+		}, nil
+	case arrow.INT8:
+		return nativeArrayValueComparator[int8, *array.Int8](), nil
+	case arrow.INT16:
+		return nativeArrayValueComparator[int16, *array.Int16](), nil
+	case arrow.INT32:
+		return nativeArrayValueComparator[int32, *array.Int32](), nil
+	case arrow.INT64:
+		return nativeArrayValueComparator[int64, *array.Int64](), nil
+	case arrow.UINT8:
+		return nativeArrayValueComparator[uint8, *array.Uint8](), nil
+	case arrow.UINT16:
+		return nativeArrayValueComparator[uint16, *array.Uint16](), nil
+	case arrow.UINT32:
+		return nativeArrayValueComparator[uint32, *array.Uint32](), nil
+	case arrow.UINT64:
+		return nativeArrayValueComparator[uint64, *array.Uint64](), nil
+	case arrow.FLOAT16:
+		return func(a1, a2 arrow.Array, i1, i2 int) int {
+			return float16ArrayValuesEqual(a1.(*array.Float16), a2.(*array.Float16), i1, i2)
+		}, nil
+	case arrow.FLOAT32:
+		return nativeArrayValueComparator[float32, *array.Float32](), nil
+	case arrow.FLOAT64:
+		return nativeArrayValueComparator[float64, *array.Float64](), nil
+	case arrow.STRING:
+		return nativeArrayValueComparator[string, *array.String](), nil
+	case arrow.LARGE_STRING:
+		return nativeArrayValueComparator[string, *array.LargeString](), nil
+	case arrow.BINARY:
+		return func(a1, a2 arrow.Array, i1, i2 int) int {
+			return binaryArrayEqual(a1.(*array.Binary), a2.(*array.Binary), i1, i2)
+		}, nil
+	case arrow.LARGE_BINARY:
+		return func(a1, a2 arrow.Array, i1, i2 int) int {
+			return bytes.Compare(a1.(*array.LargeBinary).Value(i1), a2.(*array.LargeBinary).Value(i2))
+		}, nil
+	case arrow.DATE32:
+		return nativeArrayValueComparator[arrow.Date32, *array.Date32](), nil
+	case arrow.DATE64:
+		return nativeArrayValueComparator[arrow.Date64, *array.Date64](), nil
+	case arrow.TIMESTAMP:
+		return nativeArrayValueComparator[arrow.Timestamp, *array.Timestamp](), nil
+	case arrow.TIME32:
+		return nativeArrayValueComparator[arrow.Time32, *array.Time32](), nil
+	case arrow.TIME64:
+		return nativeArrayValueComparator[arrow.Time64, *array.Time64](), nil
+	case arrow.DURATION:
+		return nativeArrayValueComparator[arrow.Duration, *array.Duration](), nil
+	default:
+		return nil, errs.NewStackError(fmt.Errorf("%w| %s", ErrUnsupportedDataType, dataType))
+	}
+}
+
+func nativeArrayValueComparator[T cmp.Ordered, E valueArray[T]]() arrayValueComparator {
+	return func(a1, a2 arrow.Array, i1, i2 int) int {
+		return cmp.Compare(a1.(E).Value(i1), a2.(E).Value(i2))
+	}
+}
@@ -0,0 +1,197 @@
+package arrowops
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/apache/arrow/go/v17/arrow"
+	"github.com/apache/arrow/go/v17/arrow/array"
+	"github.com/apache/arrow/go/v17/arrow/float16"
+	"github.com/apache/arrow/go/v17/arrow/memory"
+)
+
+func BenchmarkRowComparatorOnAllColumns(b *testing.B) {
+
+	for _, size := range TEST_SIZES {
+		b.Run(fmt.Sprintf("size_%d", size), func(b *testing.B) {
+			for idx := 0; idx < b.N; idx++ {
+				mem := memory.NewGoAllocator()
+				b.StopTimer()
+				record1 := MockData(mem, size, "ascending")
+				record2 := MockData(mem, size, "ascending")
+				defer record1.Release()
+				defer record2.Release()
+				comparator, err := NewRowComparator(record1.Schema(), record2.Schema(), SortKeysFromColumns("a", "b", "c"))
+				if err != nil {
+					b.Fatalf("received unexpected error: %s", err)
+				}
+				b.StartTimer()
+				for i := 0; i < size; i++ {
+					if val := comparator.Compare(record1, record2, i, i); val != 0 {
+						b.Errorf("expected 0, got %d", val)
+					}
+				}
+			}
+		})
+	}
+
+}
+
+func TestRowComparator(t *testing.T) {
+
+	mem := memory.NewGoAllocator()
+
+	schema := arrow.NewSchema(
+		[]arrow.Field{
+			{Name: "a", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+			{Name: "b", Type: arrow.BinaryTypes.String, Nullable: true},
+			{Name: "c", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+		}, nil)
+
+	recordBldr := func() arrow.Record {
+		recBuilder := array.NewRecordBuilder(mem, schema)
+		defer recBuilder.Release()
+
+		recBuilder.Field(0).(*array.Int64Builder).AppendValues([]int64{1, 1, 2, 0}, []bool{true, true, true, false})
+		recBuilder.Field(1).(*array.StringBuilder).AppendValues([]string{"a", "b", "b", "c"}, nil)
+		recBuilder.Field(2).(*array.Float64Builder).AppendValues([]float64{1.1, 2.2, 0, 3.3}, []bool{true, true, false, true})
+		return recBuilder.NewRecord()
+	}
+
+	testCases := []struct {
+		caseName    string
+		keys        []SortKey
+		index1Vals  []int
+		index2Vals  []int
+		expectedVal []int
+	}{
+		{
+			caseName:    "ascending_nulls_first",
+			keys:        SortKeysFromColumns("a", "b"),
+			index1Vals:  []int{0, 0, 1, 3, 2},
+			index2Vals:  []int{0, 1, 0, 0, 3},
+			expectedVal: []int{0, -1, 1, -1, 1},
+		},
+		{
+			caseName:    "descending_column",
+			keys:        []SortKey{{Column: "a"}, {Column: "b", Descending: true}},
+			index1Vals:  []int{0, 1, 2},
+			index2Vals:  []int{1, 0, 0},
+			expectedVal: []int{1, -1, 1},
+		},
+		{
+			caseName:    "nulls_last",
+			keys:        []SortKey{{Column: "a", NullsLast: true}, {Column: "c", NullsLast: true}},
+			index1Vals:  []int{3, 0, 2, 2},
+			index2Vals:  []int{0, 3, 2, 0},
+			expectedVal: []int{1, -1, 0, 1},
+		},
+		{
+			caseName:    "descending_does_not_move_nulls",
+			keys:        []SortKey{{Column: "a", Descending: true}},
+			index1Vals:  []int{3, 0},
+			index2Vals:  []int{2, 2},
+			expectedVal: []int{-1, 1},
+		},
+	}
+
+	for idx, tc := range testCases {
+		t.Run(fmt.Sprintf("case_%d:%s", idx, tc.caseName), func(t *testing.T) {
+			record1 := recordBldr()
+			record2 := recordBldr()
+			defer record1.Release()
+			defer record2.Release()
+
+			comparator, err := NewRowComparator(record1.Schema(), record2.Schema(), tc.keys)
+			if err != nil {
+				t.Fatalf("received unexpected error: %s", err)
+			}
+			for i := 0; i < len(tc.index1Vals); i++ {
+				val := comparator.Compare(record1, record2, tc.index1Vals[i], tc.index2Vals[i])
+				if val != tc.expectedVal[i] {
+					t.Errorf("[%d] expected value %d, got %d", i, tc.expectedVal[i], val)
+				}
+			}
+		})
+	}
+
+}
+
+func TestRowComparatorMatchesCompareRecordRows(t *testing.T) {
+	mem := memory.NewGoAllocator()
+
+	record1 := MockData(mem, 100, "random")
+	defer record1.Release()
+	record2 := MockData(mem, 100, "random")
+	defer record2.Release()
+
+	comparator, err := NewRowComparator(record1.Schema(), record2.Schema(), SortKeysFromColumns("a", "c"))
+	if err != nil {
+		t.Fatalf("received unexpected error: %s", err)
+	}
+
+	for i := 0; i < int(record1.NumRows()); i++ {
+		expected, err := CompareRecordRows(record1, record2, i, i, "a", "c")
+		if err != nil {
+			t.Fatalf("received unexpected error: %s", err)
+		}
+		if val := comparator.Compare(record1, record2, i, i); val != expected {
+			t.Errorf("[%d] expected value %d, got %d", i, expected, val)
+		}
+	}
+}
+
+func TestRowComparatorOrdersFloat16NaN(t *testing.T) {
+	mem := memory.NewGoAllocator()
+
+	recBuilder := array.NewRecordBuilder(mem, arrow.NewSchema(
+		[]arrow.Field{{Name: "a", Type: arrow.FixedWidthTypes.Float16}}, nil),
+	)
+	defer recBuilder.Release()
+	recBuilder.Field(0).(*array.Float16Builder).AppendValues(
+		[]float16.Num{float16.NaN(), float16.New(1), float16.NaN(), float16.Inf().Negate()}, nil,
+	)
+	record := recBuilder.NewRecord()
+	defer record.Release()
+
+	comparator, err := NewRowComparator(record.Schema(), record.Schema(), SortKeysFromColumns("a"))
+	if err != nil {
+		t.Fatalf("received unexpected error: %s", err)
+	}
+
+	index1Vals := []int{0, 1, 0, 0}
+	index2Vals := []int{1, 0, 2, 3}
+	expectedVal := []int{-1, 1, 0, -1}
+	for i := range index1Vals {
+		if val := comparator.Compare(record, record, index1Vals[i], index2Vals[i]); val != expectedVal[i] {
+			t.Errorf("[%d] expected value %d, got %d", i, expectedVal[i], val)
+		}
+	}
+}
+
+func TestNewRowComparatorErrors(t *testing.T) {
+
+	schema1 := arrow.NewSchema([]arrow.Field{{Name: "a", Type: arrow.PrimitiveTypes.Int64}}, nil)
+	schema2 := arrow.NewSchema([]arrow.Field{{Name: "a", Type: arrow.PrimitiveTypes.Int32}}, nil)
+
+	testCases := []struct {
+		caseName    string
+		keys        []SortKey
+		schema2     *arrow.Schema
+		expectedErr error
+	}{
+		{caseName: "no_keys", keys: nil, schema2: schema1, expectedErr: ErrColumnNamesRequired},
+		{caseName: "missing_column", keys: SortKeysFromColumns("b"), schema2: schema1, expectedErr: ErrColumnNotFound},
+		{caseName: "different_types", keys: SortKeysFromColumns("a"), schema2: schema2, expectedErr: ErrDataTypesNotEqual},
+	}
+
+	for idx, tc := range testCases {
+		t.Run(fmt.Sprintf("case_%d:%s", idx, tc.caseName), func(t *testing.T) {
+			_, err := NewRowComparator(schema1, tc.schema2, tc.keys)
+			if !errors.Is(err, tc.expectedErr) {
+				t.Errorf("expected error: %v, got: %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
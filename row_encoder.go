@@ -0,0 +1,531 @@
+package arrowops
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/alekLukanen/errs"
+	"github.com/apache/arrow/go/v17/arrow"
+	"github.com/apache/arrow/go/v17/arrow/array"
+	"github.com/apache/arrow/go/v17/arrow/float16"
+	"github.com/apache/arrow/go/v17/arrow/memory"
+)
+
+const (
+	rowNullsFirstMarker byte = 0x00
+	rowValidMarker      byte = 0x01
+	rowNullsLastMarker  byte = 0x02
+
+	rowBytesEscape     byte = 0x00
+	rowBytesEscapedNul byte = 0xFF
+	rowBytesTerminator byte = 0x01
+)
+
+type integer interface {
+	~int8 | ~int16 | ~int32 | ~int64
+}
+
+type unsignedInteger interface {
+	~uint8 | ~uint16 | ~uint32 | ~uint64
+}
+
+type columnEncoder struct {
+	encode func(dst []byte, arr arrow.Array, i int) []byte
+	decode func(b array.Builder, src []byte) (int, error)
+	// the number of bytes of each value, zero for escaped variable length values
+	width int
+}
+
+/*
+Encodes the key columns of a record into byte strings where comparing two
+rows with bytes.Compare gives the same result as comparing them with a
+RowComparator created with the same keys. Each column is encoded as a
+null marker byte followed by an order preserving encoding of the value.
+Fixed width values are stored big-endian with the sign bit flipped, floats
+are stored so that their bits sort in numeric order and variable length
+values are escaped and terminated. The bytes of descending columns are
+inverted. Values that compare as equal are encoded identically, so -0.0 is
+encoded as 0.0 and all NaN values share the same encoding.
+*/
+type RowEncoder struct {
+	keys       []SortKey
+	columnIdxs []int
+	schema     *arrow.Schema
+	encoders   []columnEncoder
+}
+
+/*
+Creates an encoder for records with the schema provided. When a column name
+is used more than once in the schema the first column with that name is used.
+*/
+func NewRowEncoder(schema *arrow.Schema, keys []SortKey) (*RowEncoder, error) {
+	if len(keys) == 0 {
+		return nil, errs.NewStackError(ErrColumnNamesRequired)
+	}
+
+	columnIdxs := make([]int, len(keys))
+	fields := make([]arrow.Field, len(keys))
+	encoders := make([]columnEncoder, len(keys))
+	for idx, key := range keys {
+		fieldIdxs := schema.FieldIndices(key.Column)
+		if len(fieldIdxs) == 0 {
+			return nil, errs.NewStackError(fmt.Errorf("%w| column name: %s", ErrColumnNotFound, key.Column))
+		}
+		columnIdxs[idx] = fieldIdxs[0]
+		fields[idx] = schema.Field(fieldIdxs[0])
+
+		encoder, err := newColumnEncoder(fields[idx].Type)
+		if err != nil {
+			return nil, errs.Wrap(err, fmt.Errorf("failed to create encoder for column %s", key.Column))
+		}
+		encoders[idx] = encoder
+	}
+
+	return &RowEncoder{
+		keys:       keys,
+		columnIdxs: columnIdxs,
+		schema:     arrow.NewSchema(fields, nil),
+		encoders:   encoders,
+	}, nil
+}
+
+/*
+The schema of the records returned by DecodeRows.
+*/
+func (e *RowEncoder) Schema() *arrow.Schema {
+	return e.schema
+}
+
+/*
+Appends the encoded row to dst and returns the extended slice. The record must
+have the key columns at the same positions as the schema the encoder was created
+with, use EncodeRecord for records with a different column order. The row index
+is not bounds checked.
+*/
+func (e *RowEncoder) AppendRow(dst []byte, record arrow.Record, row int) []byte {
+	return e.appendRow(dst, record, row, e.columnIdxs)
+}
+
+func (e *RowEncoder) appendRow(dst []byte, record arrow.Record, row int, columnIdxs []int) []byte {
+	for idx, key := range e.keys {
+		arr := record.Column(columnIdxs[idx])
+		if arr.IsNull(row) {
+			if key.NullsLast {
+				dst = append(dst, rowNullsLastMarker)
+			} else {
+				dst = append(dst, rowNullsFirstMarker)
+			}
+			continue
+		}
+		dst = append(dst, rowValidMarker)
+		start := len(dst)
+		dst = e.encoders[idx].encode(dst, arr, row)
+		if key.Descending {
+			invertBytes(dst[start:])
+		}
+	}
+	return dst
+}
+
+/*
+Encodes every row of the record. The resulting array has one value for
+each row in the record. The key columns are found by name so they can be
+at any position in the record.
+*/
+func (e *RowEncoder) EncodeRecord(mem *memory.GoAllocator, record arrow.Record) (*array.Binary, error) {
+	record.Retain()
+	defer record.Release()
+
+	columnIdxs, err := e.recordColumnIndices(record.Schema())
+	if err != nil {
+		return nil, err
+	}
+
+	b := array.NewBinaryBuilder(mem, arrow.BinaryTypes.Binary)
+	defer b.Release()
+	b.Reserve(int(record.NumRows()))
+
+	var row []byte
+	for i := 0; i < int(record.NumRows()); i++ {
+		row = e.appendRow(row[:0], record, i, columnIdxs)
+		b.Append(row)
+	}
+	return b.NewBinaryArray(), nil
+}
+
+/*
+Decodes rows created by this encoder back into a record containing only
+the key columns, in the order of the keys.
+*/
+func (e *RowEncoder) DecodeRows(mem *memory.GoAllocator, rows *array.Binary) (arrow.Record, error) {
+	if rows.NullN() > 0 {
+		return nil, errs.NewStackError(fmt.Errorf("%w| null values are not allowed in the rows array", ErrNullValuesNotAllowed))
+	}
+
+	rb := array.NewRecordBuilder(mem, e.schema)
+	defer rb.Release()
+	for idx := range e.keys {
+		rb.Field(idx).Reserve(rows.Len())
+	}
+
+	var scratch []byte
+	for i := 0; i < rows.Len(); i++ {
+		src := rows.Value(i)
+		for idx, key := range e.keys {
+			if len(src) == 0 {
+				return nil, errs.NewStackError(fmt.Errorf("%w| row %d is truncated", ErrRecordNotComplete, i))
+			}
+			marker := src[0]
+			src = src[1:]
+			if marker != rowValidMarker {
+				rb.Field(idx).AppendNull()
+				continue
+			}
+
+			value := src
+			if key.Descending {
+				// only the bytes of this column are inverted
+				n := e.encoders[idx].width
+				if n == 0 {
+					n = invertedEscapedBytesLength(src)
+				}
+				scratch = append(scratch[:0], src[:min(n, len(src))]...)
+				invertBytes(scratch)
+				value = scratch
+			}
+			n, err := e.encoders[idx].decode(rb.Field(idx), value)
+			if err != nil {
+				return nil, errs.Wrap(err, fmt.Errorf("failed to decode column %s in row %d", key.Column, i))
+			}
+			src = src[n:]
+		}
+	}
+
+	return rb.NewRecord(), nil
+}
+
+/*
+Finds the index of each key column in the schema, which may have the key
+columns in a different order than the schema the encoder was created with.
+*/
+func (e *RowEncoder) recordColumnIndices(schema *arrow.Schema) ([]int, error) {
+	if !SchemaSubSetEqual(e.schema, schema, e.schemaColumnNames()...) {
+		return nil, errs.NewStackError(fmt.Errorf("%w| record schema does not match the encoder schema", ErrSchemasNotEqual))
+	}
+	columnIdxs := make([]int, len(e.keys))
+	for idx, key := range e.keys {
+		columnIdxs[idx] = schema.FieldIndices(key.Column)[0]
+	}
+	return columnIdxs, nil
+}
+
+func (e *RowEncoder) schemaColumnNames() []string {
+	names := make([]string, len(e.keys))
+	for idx, key := range e.keys {
+		names[idx] = key.Column
+	}
+	return names
+}
+
+func invertBytes(b []byte) {
+	for i := range b {
+		b[i] = ^b[i]
+	}
+}
+
+func newColumnEncoder(dataType arrow.DataType) (columnEncoder, error) {
+	switch dataType.ID() {
+	case arrow.BOOL:
+		return columnEncoder{encode: encodeBoolean, decode: decodeBoolean, width: 1}, nil
+	case arrow.INT8:
+		return intColumnEncoder[int8, *array.Int8](1), nil
+	case arrow.INT16:
+		return intColumnEncoder[int16, *array.Int16](2), nil
+	case arrow.INT32:
+		return intColumnEncoder[int32, *array.Int32](4), nil
+	case arrow.INT64:
+		return intColumnEncoder[int64, *array.Int64](8), nil
+	case arrow.UINT8:
+		return uintColumnEncoder[uint8, *array.Uint8](1), nil
+	case arrow.UINT16:
+		return uintColumnEncoder[uint16, *array.Uint16](2), nil
+	case arrow.UINT32:
+		return uintColumnEncoder[uint32, *array.Uint32](4), nil
+	case arrow.UINT64:
+		return uintColumnEncoder[uint64, *array.Uint64](8), nil
+	case arrow.FLOAT16:
+		return columnEncoder{encode: encodeFloat16, decode: decodeFloat16, width: 2}, nil
+	case arrow.FLOAT32:
+		return columnEncoder{encode: encodeFloat32, decode: decodeFloat32, width: 4}, nil
+	case arrow.FLOAT64:
+		return columnEncoder{encode: encodeFloat64, decode: decodeFloat64, width: 8}, nil
+	case arrow.STRING:
+		return columnEncoder{
+			encode: func(dst []byte, arr arrow.Array, i int) []byte {
+				return appendEscapedBytes(dst, []byte(arr.(*array.String).Value(i)))
+			},
+			decode: func(b array.Builder, src []byte) (int, error) {
+				value, n, err := readEscapedBytes(src)
+				if err != nil {
+					return 0, err
+				}
+				b.(*array.StringBuilder).Append(string(value))
+				return n, nil
+			},
+		}, nil
+	case arrow.LARGE_STRING:
+		return columnEncoder{
+			encode: func(dst []byte, arr arrow.Array, i int) []byte {
+				return appendEscapedBytes(dst, []byte(arr.(*array.LargeString).Value(i)))
+			},
+			decode: func(b array.Builder, src []byte) (int, error) {
+				value, n, err := readEscapedBytes(src)
+				if err != nil {
+					return 0, err
+				}
+				b.(*array.LargeStringBuilder).Append(string(value))
+				return n, nil
+			},
+		}, nil
+	case arrow.BINARY, arrow.LARGE_BINARY:
+		return columnEncoder{
+			encode: func(dst []byte, arr arrow.Array, i int) []byte {
+				return appendEscapedBytes(dst, arr.(binaryValueArray).Value(i))
+			},
+			decode: func(b array.Builder, src []byte) (int, error) {
+				value, n, err := readEscapedBytes(src)
+				if err != nil {
+					return 0, err
+				}
+				b.(*array.BinaryBuilder).Append(value)
+				return n, nil
+			},
+		}, nil
+	case arrow.DATE32:
+		return intColumnEncoder[arrow.Date32, *array.Date32](4), nil
+	case arrow.DATE64:
+		return intColumnEncoder[arrow.Date64, *array.Date64](8), nil
+	case arrow.TIMESTAMP:
+		return intColumnEncoder[arrow.Timestamp, *array.Timestamp](8), nil
+	case arrow.TIME32:
+		return intColumnEncoder[arrow.Time32, *array.Time32](4), nil
+	case arrow.TIME64:
+		return intColumnEncoder[arrow.Time64, *array.Time64](8), nil
+	case arrow.DURATION:
+		return intColumnEncoder[arrow.Duration, *array.Duration](8), nil
+	default:
+		return columnEncoder{}, errs.NewStackError(fmt.Errorf("%w| %s", ErrUnsupportedDataType, dataType))
+	}
+}
+
+func appendBigEndian(dst []byte, v uint64, width int) []byte {
+	for shift := (width - 1) * 8; shift >= 0; shift -= 8 {
+		dst = append(dst, byte(v>>shift))
+	}
+	return dst
+}
+
+func readBigEndian(src []byte, width int) (uint64, error) {
+	if len(src) < width {
+		return 0, errs.NewStackError(fmt.Errorf("%w| expected %d bytes, got %d", ErrRecordNotComplete, width, len(src)))
+	}
+	var v uint64
+	for i := 0; i < width; i++ {
+		v = v<<8 | uint64(src[i])
+	}
+	return v, nil
+}
+
+func intColumnEncoder[T integer, E valueArray[T]](width int) columnEncoder {
+	signBit := uint64(1) << (width*8 - 1)
+	mask := uint64(math.MaxUint64) >> (64 - width*8)
+	return columnEncoder{
+		encode: func(dst []byte, arr arrow.Array, i int) []byte {
+			v := uint64(int64(arr.(E).Value(i))) & mask
+			return appendBigEndian(dst, v^signBit, width)
+		},
+		decode: func(b array.Builder, src []byte) (int, error) {
+			v, err := readBigEndian(src, width)
+			if err != nil {
+				return 0, err
+			}
+			v ^= signBit
+			// sign extend the value back to 64 bits
+			shift := 64 - width*8
+			b.(arrayBuilder[T]).Append(T(int64(v<<shift) >> shift))
+			return width, nil
+		},
+		width: width,
+	}
+}
+
+func uintColumnEncoder[T unsignedInteger, E valueArray[T]](width int) columnEncoder {
+	return columnEncoder{
+		encode: func(dst []byte, arr arrow.Array, i int) []byte {
+			return appendBigEndian(dst, uint64(arr.(E).Value(i)), width)
+		},
+		decode: func(b array.Builder, src []byte) (int, error) {
+			v, err := readBigEndian(src, width)
+			if err != nil {
+				return 0, err
+			}
+			b.(arrayBuilder[T]).Append(T(v))
+			return width, nil
+		},
+		width: width,
+	}
+}
+
+func encodeBoolean(dst []byte, arr arrow.Array, i int) []byte {
+	if arr.(*array.Boolean).Value(i) {
+		return append(dst, 1)
+	}
+	return append(dst, 0)
+}
+
+func decodeBoolean(b array.Builder, src []byte) (int, error) {
+	if len(src) < 1 {
+		return 0, errs.NewStackError(fmt.Errorf("%w| expected 1 byte, got 0", ErrRecordNotComplete))
+	}
+	b.(*array.BooleanBuilder).Append(src[0] != 0)
+	return 1, nil
+}
+
+/*
+Maps the bits of a float onto an unsigned integer which sorts in the same
+order as the float. NaN values are mapped to zero so they sort before all
+other values, which matches cmp.Compare.
+*/
+func orderedFloatBits(bits uint64, width int, isNaN bool) uint64 {
+	if isNaN {
+		return 0
+	}
+	signBit := uint64(1) << (width*8 - 1)
+	if bits == signBit {
+		// negative zero
+		bits = 0
+	}
+	if bits&signBit != 0 {
+		return ^bits & (signBit<<1 - 1)
+	}
+	return bits | signBit
+}
+
+func floatBitsFromOrdered(v uint64, width int) uint64 {
+	signBit := uint64(1) << (width*8 - 1)
+	if v&signBit != 0 {
+		return v &^ signBit
+	}
+	return ^v & (signBit<<1 - 1)
+}
+
+func encodeFloat16(dst []byte, arr arrow.Array, i int) []byte {
+	v := arr.(*array.Float16).Value(i)
+	return appendBigEndian(dst, orderedFloatBits(uint64(v.Uint16()), 2, v.IsNaN()), 2)
+}
+
+func decodeFloat16(b array.Builder, src []byte) (int, error) {
+	v, err := readBigEndian(src, 2)
+	if err != nil {
+		return 0, err
+	}
+	if v == 0 {
+		b.(*array.Float16Builder).Append(float16.NaN())
+	} else {
+		b.(*array.Float16Builder).Append(float16.FromBits(uint16(floatBitsFromOrdered(v, 2))))
+	}
+	return 2, nil
+}
+
+func encodeFloat32(dst []byte, arr arrow.Array, i int) []byte {
+	v := arr.(*array.Float32).Value(i)
+	return appendBigEndian(dst, orderedFloatBits(uint64(math.Float32bits(v)), 4, v != v), 4)
+}
+
+func decodeFloat32(b array.Builder, src []byte) (int, error) {
+	v, err := readBigEndian(src, 4)
+	if err != nil {
+		return 0, err
+	}
+	if v == 0 {
+		b.(*array.Float32Builder).Append(float32(math.NaN()))
+	} else {
+		b.(*array.Float32Builder).Append(math.Float32frombits(uint32(floatBitsFromOrdered(v, 4))))
+	}
+	return 4, nil
+}
+
+func encodeFloat64(dst []byte, arr arrow.Array, i int) []byte {
+	v := arr.(*array.Float64).Value(i)
+	return appendBigEndian(dst, orderedFloatBits(math.Float64bits(v), 8, math.IsNaN(v)), 8)
+}
+
+func decodeFloat64(b array.Builder, src []byte) (int, error) {
+	v, err := readBigEndian(src, 8)
+	if err != nil {
+		return 0, err
+	}
+	if v == 0 {
+		b.(*array.Float64Builder).Append(math.NaN())
+	} else {
+		b.(*array.Float64Builder).Append(math.Float64frombits(floatBitsFromOrdered(v, 8)))
+	}
+	return 8, nil
+}
+
+/*
+Appends the value with each zero byte escaped as 0x00 0xFF followed by the
+0x00 0x01 terminator. The terminator sorts before any escaped zero byte and
+any other byte so shorter prefixes always sort first.
+*/
+func appendEscapedBytes(dst []byte, value []byte) []byte {
+	for _, b := range value {
+		if b == rowBytesEscape {
+			dst = append(dst, rowBytesEscape, rowBytesEscapedNul)
+		} else {
+			dst = append(dst, b)
+		}
+	}
+	return append(dst, rowBytesEscape, rowBytesTerminator)
+}
+
+/*
+The length of the escaped value at the start of src when its bytes are
+inverted, including the terminator, or the length of src when the
+terminator is missing.
+*/
+func invertedEscapedBytesLength(src []byte) int {
+	for i := 0; i+1 < len(src); i++ {
+		if ^src[i] != rowBytesEscape {
+			continue
+		}
+		if ^src[i+1] == rowBytesTerminator {
+			return i + 2
+		}
+		i++
+	}
+	return len(src)
+}
+
+func readEscapedBytes(src []byte) ([]byte, int, error) {
+	value := make([]byte, 0, len(src))
+	for i := 0; i < len(src); i++ {
+		if src[i] != rowBytesEscape {
+			value = append(value, src[i])
+			continue
+		}
+		if i+1 >= len(src) {
+			break
+		}
+		switch src[i+1] {
+		case rowBytesTerminator:
+			return value, i + 2, nil
+		case rowBytesEscapedNul:
+			value = append(value, 0)
+			i++
+		default:
+			return nil, 0, errs.NewStackError(fmt.Errorf("%w| invalid escape sequence", ErrRecordNotComplete))
+		}
+	}
+	return nil, 0, errs.NewStackError(fmt.Errorf("%w| missing terminator", ErrRecordNotComplete))
+}
@@ -0,0 +1,284 @@
+package arrowops
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/apache/arrow/go/v17/arrow"
+	"github.com/apache/arrow/go/v17/arrow/array"
+	"github.com/apache/arrow/go/v17/arrow/float16"
+	"github.com/apache/arrow/go/v17/arrow/memory"
+)
+
+func BenchmarkRowEncoderEncodeRecord(b *testing.B) {
+	for _, size := range TEST_SIZES {
+		b.Run(fmt.Sprintf("size=%d", size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				mem := memory.NewGoAllocator()
+				r1 := MockData(mem, size, "random")
+				defer r1.Release()
+				encoder, err := NewRowEncoder(r1.Schema(), SortKeysFromColumns("a", "b", "c"))
+				if err != nil {
+					b.Fatalf("received error while creating encoder '%s'", err)
+				}
+				b.StartTimer()
+				rows, err := encoder.EncodeRecord(mem, r1)
+				if err != nil {
+					b.Fatalf("received error while encoding record '%s'", err)
+				}
+				rows.Release()
+			}
+		})
+	}
+}
+
+func TestRowEncoderPreservesOrder(t *testing.T) {
+
+	mem := memory.NewGoAllocator()
+
+	recordBldr := func() arrow.Record {
+		recBuilder := array.NewRecordBuilder(
+			mem, arrow.NewSchema(
+				[]arrow.Field{
+					{Name: "a", Type: arrow.PrimitiveTypes.Int32, Nullable: true},
+					{Name: "b", Type: arrow.BinaryTypes.String, Nullable: true},
+					{Name: "c", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+					{Name: "d", Type: arrow.PrimitiveTypes.Uint16, Nullable: true},
+					{Name: "e", Type: arrow.FixedWidthTypes.Boolean, Nullable: true},
+					{Name: "f", Type: arrow.FixedWidthTypes.Timestamp_ms, Nullable: true},
+				}, nil),
+		)
+		defer recBuilder.Release()
+
+		recBuilder.Field(0).(*array.Int32Builder).AppendValues(
+			[]int32{-5, 3, 0, math.MinInt32, math.MaxInt32, 3, 3, -1},
+			[]bool{true, true, false, true, true, true, true, true},
+		)
+		recBuilder.Field(1).(*array.StringBuilder).AppendValues(
+			[]string{"a", "a\x00", "", "ab", "b", "a", "", "zz"},
+			[]bool{true, true, true, true, true, true, false, true},
+		)
+		recBuilder.Field(2).(*array.Float64Builder).AppendValues(
+			[]float64{1.5, -2.25, math.Inf(1), math.Inf(-1), 0, -0.5, 1e300, -1e-300},
+			[]bool{true, true, true, true, false, true, true, true},
+		)
+		recBuilder.Field(3).(*array.Uint16Builder).AppendValues(
+			[]uint16{1, math.MaxUint16, 0, 7, 7, 1, 2, 3},
+			nil,
+		)
+		recBuilder.Field(4).(*array.BooleanBuilder).AppendValues(
+			[]bool{true, false, true, false, true, true, false, false},
+			[]bool{true, true, true, true, true, false, true, true},
+		)
+		recBuilder.Field(5).(*array.TimestampBuilder).AppendValues(
+			[]arrow.Timestamp{-100, 0, 100, 5, 5, -1, 1 << 40, 2},
+			nil,
+		)
+		return recBuilder.NewRecord()
+	}
+
+	testCases := []struct {
+		caseName string
+		keys     []SortKey
+	}{
+		{caseName: "single_int_column", keys: SortKeysFromColumns("a")},
+		{caseName: "string_with_zero_bytes", keys: SortKeysFromColumns("b", "a")},
+		{caseName: "float_column", keys: SortKeysFromColumns("c")},
+		{caseName: "all_columns", keys: SortKeysFromColumns("a", "b", "c", "d", "e", "f")},
+		{
+			caseName: "descending_and_nulls_last",
+			keys: []SortKey{
+				{Column: "b", Descending: true},
+				{Column: "a", NullsLast: true},
+				{Column: "c", Descending: true, NullsLast: true},
+				{Column: "e", Descending: true},
+			},
+		},
+	}
+
+	for idx, tc := range testCases {
+		t.Run(fmt.Sprintf("case_%d:%s", idx, tc.caseName), func(t *testing.T) {
+			record := recordBldr()
+			defer record.Release()
+
+			encoder, err := NewRowEncoder(record.Schema(), tc.keys)
+			if err != nil {
+				t.Fatalf("received unexpected error: %s", err)
+			}
+			comparator, err := NewRowComparator(record.Schema(), record.Schema(), tc.keys)
+			if err != nil {
+				t.Fatalf("received unexpected error: %s", err)
+			}
+
+			rows, err := encoder.EncodeRecord(mem, record)
+			if err != nil {
+				t.Fatalf("received unexpected error: %s", err)
+			}
+			defer rows.Release()
+
+			for i := 0; i < int(record.NumRows()); i++ {
+				for j := 0; j < int(record.NumRows()); j++ {
+					expected := comparator.Compare(record, record, i, j)
+					actual := bytes.Compare(rows.Value(i), rows.Value(j))
+					if expected != actual {
+						t.Errorf("rows (%d, %d) expected comparison %d, got %d", i, j, expected, actual)
+					}
+				}
+			}
+
+			decodedRecord, err := encoder.DecodeRows(mem, rows)
+			if err != nil {
+				t.Fatalf("received unexpected error: %s", err)
+			}
+			defer decodedRecord.Release()
+
+			expectedRecord, err := TakeRecordColumns(record, encoder.schemaColumnNames())
+			if err != nil {
+				t.Fatalf("received unexpected error: %s", err)
+			}
+			defer expectedRecord.Release()
+
+			if !array.RecordEqual(expectedRecord, decodedRecord) {
+				t.Errorf("expected record: %v, got: %v", expectedRecord, decodedRecord)
+			}
+		})
+	}
+
+}
+
+func TestRowEncoderNormalizesFloats(t *testing.T) {
+	mem := memory.NewGoAllocator()
+
+	recBuilder := array.NewRecordBuilder(mem, arrow.NewSchema(
+		[]arrow.Field{{Name: "a", Type: arrow.PrimitiveTypes.Float32}}, nil),
+	)
+	defer recBuilder.Release()
+	recBuilder.Field(0).(*array.Float32Builder).AppendValues(
+		[]float32{float32(math.Copysign(0, -1)), 0, float32(math.NaN()), float32(math.Inf(-1))}, nil,
+	)
+	record := recBuilder.NewRecord()
+	defer record.Release()
+
+	encoder, err := NewRowEncoder(record.Schema(), SortKeysFromColumns("a"))
+	if err != nil {
+		t.Fatalf("received unexpected error: %s", err)
+	}
+	rows, err := encoder.EncodeRecord(mem, record)
+	if err != nil {
+		t.Fatalf("received unexpected error: %s", err)
+	}
+	defer rows.Release()
+
+	if !bytes.Equal(rows.Value(0), rows.Value(1)) {
+		t.Errorf("expected -0.0 and 0.0 to have the same encoding")
+	}
+	if bytes.Compare(rows.Value(2), rows.Value(3)) >= 0 {
+		t.Errorf("expected NaN to sort before -Inf")
+	}
+}
+
+func TestRowEncoderOrdersFloat16NaNLikeComparator(t *testing.T) {
+	mem := memory.NewGoAllocator()
+
+	recBuilder := array.NewRecordBuilder(mem, arrow.NewSchema(
+		[]arrow.Field{{Name: "a", Type: arrow.FixedWidthTypes.Float16}}, nil),
+	)
+	defer recBuilder.Release()
+	recBuilder.Field(0).(*array.Float16Builder).AppendValues(
+		[]float16.Num{float16.NaN(), float16.New(1), float16.NaN(), float16.Inf().Negate()}, nil,
+	)
+	record := recBuilder.NewRecord()
+	defer record.Release()
+
+	encoder, err := NewRowEncoder(record.Schema(), SortKeysFromColumns("a"))
+	if err != nil {
+		t.Fatalf("received unexpected error: %s", err)
+	}
+	comparator, err := NewRowComparator(record.Schema(), record.Schema(), SortKeysFromColumns("a"))
+	if err != nil {
+		t.Fatalf("received unexpected error: %s", err)
+	}
+	rows, err := encoder.EncodeRecord(mem, record)
+	if err != nil {
+		t.Fatalf("received unexpected error: %s", err)
+	}
+	defer rows.Release()
+
+	for i := 0; i < rows.Len(); i++ {
+		for j := 0; j < rows.Len(); j++ {
+			expected := comparator.Compare(record, record, i, j)
+			if actual := bytes.Compare(rows.Value(i), rows.Value(j)); expected != actual {
+				t.Errorf("rows (%d, %d) expected comparison %d, got %d", i, j, expected, actual)
+			}
+		}
+	}
+}
+
+func TestRowEncoderEncodesReorderedColumns(t *testing.T) {
+	mem := memory.NewGoAllocator()
+
+	record := recordFromJSON(t, mem, arrow.NewSchema(
+		[]arrow.Field{
+			{Name: "a", Type: arrow.PrimitiveTypes.Int32, Nullable: true},
+			{Name: "b", Type: arrow.BinaryTypes.String, Nullable: true},
+		}, nil),
+		`[{"a": 1, "b": "x"}, {"a": null, "b": "y"}, {"a": -3, "b": null}]`,
+	)
+	defer record.Release()
+	reordered, err := TakeRecordColumns(record, []string{"b", "a"})
+	if err != nil {
+		t.Fatalf("received unexpected error: %s", err)
+	}
+	defer reordered.Release()
+
+	encoder, err := NewRowEncoder(record.Schema(), SortKeysFromColumns("a", "b"))
+	if err != nil {
+		t.Fatalf("received unexpected error: %s", err)
+	}
+	rows, err := encoder.EncodeRecord(mem, record)
+	if err != nil {
+		t.Fatalf("received unexpected error: %s", err)
+	}
+	defer rows.Release()
+	reorderedRows, err := encoder.EncodeRecord(mem, reordered)
+	if err != nil {
+		t.Fatalf("received unexpected error: %s", err)
+	}
+	defer reorderedRows.Release()
+
+	for i := 0; i < rows.Len(); i++ {
+		if !bytes.Equal(rows.Value(i), reorderedRows.Value(i)) {
+			t.Errorf("expected row %d to have the same encoding in both records", i)
+		}
+	}
+}
+
+func TestRowEncoderErrors(t *testing.T) {
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "a", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "b", Type: arrow.ListOf(arrow.PrimitiveTypes.Int64)},
+	}, nil)
+
+	testCases := []struct {
+		caseName    string
+		keys        []SortKey
+		expectedErr error
+	}{
+		{caseName: "no_keys", keys: nil, expectedErr: ErrColumnNamesRequired},
+		{caseName: "missing_column", keys: SortKeysFromColumns("z"), expectedErr: ErrColumnNotFound},
+		{caseName: "unsupported_type", keys: SortKeysFromColumns("a", "b"), expectedErr: ErrUnsupportedDataType},
+	}
+
+	for idx, tc := range testCases {
+		t.Run(fmt.Sprintf("case_%d:%s", idx, tc.caseName), func(t *testing.T) {
+			_, err := NewRowEncoder(schema, tc.keys)
+			if !errors.Is(err, tc.expectedErr) {
+				t.Errorf("expected error: %v, got: %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
@@ -241,3 +241,36 @@ func nativeRankArray[E comparable, T valueArray[E]](mem *memory.GoAllocator, arr
 	builder.AppendValues(ranks, nil)
 	return builder.NewUint32Array(), nil
 }
+
+/*
+Returns the indices of the rows in the record in the order defined by the sort keys.
+Unlike SortRecord each key can be sorted in ascending or descending order with nulls
+first or last, and any number of keys can be used. The sort is stable so rows with
+equal keys keep their order from the input record. The indices can be used with
+TakeRecord to create the sorted record.
+*/
+func SortRecordIndices(mem *memory.GoAllocator, record arrow.Record, keys []SortKey) (*array.Uint32, error) {
+	indices, err := sortRowIndices(record, keys)
+	if err != nil {
+		return nil, err
+	}
+	indicesBuilder := array.NewUint32Builder(mem)
+	defer indicesBuilder.Release()
+	indicesBuilder.AppendValues(indices, nil)
+	return indicesBuilder.NewUint32Array(), nil
+}
+
+func sortRowIndices(record arrow.Record, keys []SortKey) ([]uint32, error) {
+	comparator, err := NewRowComparator(record.Schema(), record.Schema(), keys)
+	if err != nil {
+		return nil, errs.Wrap(err, fmt.Errorf("failed to create comparator for sort keys"))
+	}
+	indices := make([]uint32, record.NumRows())
+	for i := range indices {
+		indices[i] = uint32(i)
+	}
+	slices.SortStableFunc(indices, func(i, j uint32) int {
+		return comparator.Compare(record, record, int(i), int(j))
+	})
+	return indices, nil
+}
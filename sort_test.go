@@ -2,6 +2,7 @@ package arrowops
 
 import (
 	"fmt"
+	"slices"
 	"testing"
 
 	"github.com/apache/arrow/go/v17/arrow"
@@ -123,3 +124,60 @@ func TestSortRecord(t *testing.T) {
 	}
 
 }
+
+func TestSortRecordIndices(t *testing.T) {
+
+	mem := memory.NewGoAllocator()
+
+	rb1 := array.NewRecordBuilder(mem, arrow.NewSchema(
+		[]arrow.Field{
+			{Name: "a", Type: arrow.PrimitiveTypes.Uint32, Nullable: true},
+			{Name: "b", Type: arrow.PrimitiveTypes.Float32},
+			{Name: "c", Type: arrow.BinaryTypes.String},
+		}, nil))
+	defer rb1.Release()
+
+	rb1.Field(0).(*array.Uint32Builder).AppendValues([]uint32{4, 4, 3, 0, 1, 3}, []bool{true, true, true, false, true, true})
+	rb1.Field(1).(*array.Float32Builder).AppendValues([]float32{1.0, 2.0, 3.0, 2.0, 1.0, 3.0}, nil)
+	rb1.Field(2).(*array.StringBuilder).AppendValues([]string{"s1", "s2", "s3", "s4", "s5", "s6"}, nil)
+
+	r1 := rb1.NewRecord()
+	defer r1.Release()
+
+	testCases := []struct {
+		caseName        string
+		keys            []SortKey
+		expectedIndices []uint32
+	}{
+		{
+			caseName:        "ascending_nulls_first",
+			keys:            SortKeysFromColumns("a", "b"),
+			expectedIndices: []uint32{3, 4, 2, 5, 0, 1},
+		},
+		{
+			caseName:        "descending_nulls_last",
+			keys:            []SortKey{{Column: "a", Descending: true, NullsLast: true}, {Column: "b", Descending: true}},
+			expectedIndices: []uint32{1, 0, 2, 5, 4, 3},
+		},
+		{
+			caseName:        "stable_for_equal_keys",
+			keys:            []SortKey{{Column: "b"}},
+			expectedIndices: []uint32{0, 4, 1, 3, 2, 5},
+		},
+	}
+
+	for idx, tc := range testCases {
+		t.Run(fmt.Sprintf("case_%d:%s", idx, tc.caseName), func(t *testing.T) {
+			indices, err := SortRecordIndices(mem, r1, tc.keys)
+			if err != nil {
+				t.Fatalf("received unexpected error: %s", err)
+			}
+			defer indices.Release()
+
+			if !slices.Equal(indices.Uint32Values(), tc.expectedIndices) {
+				t.Errorf("expected indices %v, got %v", tc.expectedIndices, indices.Uint32Values())
+			}
+		})
+	}
+
+}
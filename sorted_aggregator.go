@@ -0,0 +1,250 @@
+package arrowops
+
+import (
+	"fmt"
+
+	"github.com/alekLukanen/errs"
+	"github.com/apache/arrow/go/v17/arrow"
+	"github.com/apache/arrow/go/v17/arrow/array"
+	"github.com/apache/arrow/go/v17/arrow/memory"
+)
+
+/*
+Aggregates a sequence of records sorted by a set of key columns. Group boundaries
+are found by comparing adjacent rows, so only the state of the group currently
+being accumulated is kept. The last group of each record stays open because the
+next record may continue it, call Finish after the last record to emit it.
+*/
+type SortedAggregator struct {
+	mem          *memory.GoAllocator
+	schema       *arrow.Schema
+	keys         []string
+	aggregations []Aggregation
+	aggregators  []aggregator
+	columnIdxs   []int
+
+	comparator *RowComparator
+	// compares a row of a record with the key of the open group
+	openComparator *RowComparator
+	// a single row record with the key columns of the open group,
+	// nil when no group is open
+	openKeys arrow.Record
+}
+
+/*
+Creates an aggregator for records with the schema provided. The records must be
+sorted in ascending order by the keys with null values first, the order produced
+by SortRecordIndices with SortKeysFromColumns, across all of the records.
+*/
+func NewSortedAggregator(mem *memory.GoAllocator, schema *arrow.Schema, keys []string, aggregations ...Aggregation) (*SortedAggregator, error) {
+	if len(keys) == 0 {
+		return nil, errs.NewStackError(ErrColumnNamesRequired)
+	}
+	keyIdxs, err := hashColumnIndices(schema, keys)
+	if err != nil {
+		return nil, errs.Wrap(err, fmt.Errorf("failed to find keys %v", keys))
+	}
+	sortKeys := SortKeysFromColumns(keys...)
+	comparator, err := newRowComparator(schema, schema, keyIdxs, keyIdxs, sortKeys)
+	if err != nil {
+		return nil, errs.Wrap(err, fmt.Errorf("failed to create comparator for keys %v", keys))
+	}
+
+	keyFields := make([]arrow.Field, len(keyIdxs))
+	openKeyIdxs := make([]int, len(keyIdxs))
+	for idx, keyIdx := range keyIdxs {
+		keyFields[idx] = schema.Field(keyIdx)
+		openKeyIdxs[idx] = idx
+	}
+	openComparator, err := newRowComparator(schema, arrow.NewSchema(keyFields, nil), keyIdxs, openKeyIdxs, sortKeys)
+	if err != nil {
+		return nil, errs.Wrap(err, fmt.Errorf("failed to create comparator for keys %v", keys))
+	}
+
+	aggregators, columnIdxs, err := newAggregators(schema, aggregations)
+	if err != nil {
+		return nil, err
+	}
+	return &SortedAggregator{
+		mem:            mem,
+		schema:         schema,
+		keys:           keys,
+		aggregations:   aggregations,
+		aggregators:    aggregators,
+		columnIdxs:     columnIdxs,
+		comparator:     comparator,
+		openComparator: openComparator,
+	}, nil
+}
+
+/*
+Adds the rows of the record to the aggregation and returns the groups that were
+completed by the record, in key order, in the same format as Aggregate on a
+GroupedRecord. Returns ErrNotSorted if the rows are not sorted by the keys.
+*/
+func (a *SortedAggregator) Aggregate(record arrow.Record) (arrow.Record, error) {
+	record.Retain()
+	defer record.Release()
+
+	if !a.schema.Equal(record.Schema()) {
+		return nil, errs.NewStackError(fmt.Errorf("%w| record schema does not match the aggregator schema", ErrSchemasNotEqual))
+	}
+
+	numRows := int(record.NumRows())
+	carried := a.openKeys != nil
+	groupIDs := make([]uint32, numRows)
+	// the first row of each group that starts in this record
+	firstRows := make([]uint32, 0)
+
+	group := 0
+	if numRows > 0 {
+		if carried {
+			compareValue := a.openComparator.Compare(record, a.openKeys, 0, 0)
+			if compareValue < 0 {
+				return nil, errs.NewStackError(fmt.Errorf("%w| first row is less than the last row of the previous record", ErrNotSorted))
+			}
+			if compareValue > 0 {
+				group++
+				firstRows = append(firstRows, 0)
+			}
+		} else {
+			firstRows = append(firstRows, 0)
+		}
+		groupIDs[0] = uint32(group)
+	}
+	for i := 1; i < numRows; i++ {
+		compareValue := a.comparator.Compare(record, record, i-1, i)
+		if compareValue > 0 {
+			return nil, errs.NewStackError(fmt.Errorf("%w| row %d is greater than row %d", ErrNotSorted, i-1, i))
+		}
+		if compareValue < 0 {
+			group++
+			firstRows = append(firstRows, uint32(i))
+		}
+		groupIDs[i] = uint32(group)
+	}
+
+	numGroups := 0
+	if numRows > 0 {
+		numGroups = group + 1
+	} else if carried {
+		numGroups = 1
+	}
+	numClosed := max(numGroups-1, 0)
+
+	// the key rows of the closed groups that started in this record, the
+	// last group to start in this record is the new open group
+	closedRows := firstRows
+	if len(firstRows) > 0 {
+		closedRows = firstRows[:len(firstRows)-1]
+	}
+
+	results, err := a.update(record, groupIDs, numGroups, numClosed)
+	if err != nil {
+		return nil, err
+	}
+	defer releaseArrays(results)
+
+	keys, err := takeGroupKeys(a.mem, record, a.keys, closedRows)
+	if err != nil {
+		return nil, err
+	}
+	defer keys.Release()
+	if carried && numClosed > 0 {
+		withOpenKeys, err := ConcatenateRecords(a.mem, a.openKeys, keys)
+		if err != nil {
+			return nil, errs.Wrap(err, fmt.Errorf("failed to add the key of the previous record's group"))
+		}
+		defer withOpenKeys.Release()
+		keys = withOpenKeys
+	}
+
+	if len(firstRows) > 0 {
+		openKeys, err := takeGroupKeys(a.mem, record, a.keys, firstRows[len(firstRows)-1:])
+		if err != nil {
+			return nil, err
+		}
+		if a.openKeys != nil {
+			a.openKeys.Release()
+		}
+		a.openKeys = openKeys
+	}
+
+	return aggregateOutput(keys, a.aggregations, results), nil
+}
+
+/*
+Emits the open group, if there is one, in the same format as Aggregate.
+The aggregator can be used for a new sequence of records afterwards.
+*/
+func (a *SortedAggregator) Finish() (arrow.Record, error) {
+	numGroups := 0
+	if a.openKeys != nil {
+		numGroups = 1
+	}
+	results, err := a.update(nil, nil, numGroups, numGroups)
+	if err != nil {
+		return nil, err
+	}
+	defer releaseArrays(results)
+
+	if a.openKeys == nil {
+		empty := emptyRecord(a.mem, a.schema)
+		defer empty.Release()
+		keys, err := takeGroupKeys(a.mem, empty, a.keys, nil)
+		if err != nil {
+			return nil, err
+		}
+		defer keys.Release()
+		return aggregateOutput(keys, a.aggregations, results), nil
+	}
+
+	keys := a.openKeys
+	a.openKeys = nil
+	defer keys.Release()
+	return aggregateOutput(keys, a.aggregations, results), nil
+}
+
+func (a *SortedAggregator) Release() {
+	for _, agg := range a.aggregators {
+		agg.release()
+	}
+	if a.openKeys != nil {
+		a.openKeys.Release()
+		a.openKeys = nil
+	}
+}
+
+/*
+Updates each aggregator with the rows of the record, when one is provided,
+and flushes the closed groups.
+*/
+func (a *SortedAggregator) update(record arrow.Record, groupIDs []uint32, numGroups, numClosed int) ([]arrow.Array, error) {
+	results := make([]arrow.Array, 0, len(a.aggregators))
+	for idx, agg := range a.aggregators {
+		agg.resize(numGroups)
+		if record != nil {
+			var column arrow.Array
+			if a.columnIdxs[idx] != -1 {
+				column = record.Column(a.columnIdxs[idx])
+			}
+			agg.update(column, groupIDs)
+		}
+		result, err := agg.flush(a.mem, numClosed)
+		if err != nil {
+			releaseArrays(results)
+			return nil, errs.Wrap(err, fmt.Errorf("failed to aggregate %s", a.aggregations[idx].Name()))
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+func emptyRecord(mem *memory.GoAllocator, schema *arrow.Schema) arrow.Record {
+	columns := make([]arrow.Array, schema.NumFields())
+	for i, field := range schema.Fields() {
+		columns[i] = array.MakeArrayOfNull(mem, field.Type, 0)
+		defer columns[i].Release()
+	}
+	return array.NewRecord(schema, columns, 0)
+}
@@ -0,0 +1,168 @@
+package arrowops
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/apache/arrow/go/v17/arrow"
+	"github.com/apache/arrow/go/v17/arrow/memory"
+)
+
+func TestSortedAggregator(t *testing.T) {
+
+	mem := memory.NewGoAllocator()
+
+	record := recordFromJSON(t, mem, arrow.NewSchema(
+		[]arrow.Field{
+			{Name: "user", Type: arrow.BinaryTypes.String, Nullable: true},
+			{Name: "amount", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+			{Name: "ts", Type: arrow.PrimitiveTypes.Int32},
+			{Name: "latency", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+		}, nil), `[
+			{"user": null, "amount": 5, "ts": 7, "latency": null},
+			{"user": null, "amount": 1, "ts": 2, "latency": 4},
+			{"user": "u1", "amount": 10, "ts": 5, "latency": 1},
+			{"user": "u1", "amount": null, "ts": 1, "latency": 3},
+			{"user": "u1", "amount": 30, "ts": 9, "latency": 5},
+			{"user": "u2", "amount": 20, "ts": 3, "latency": 2},
+			{"user": "u2", "amount": 20, "ts": 8, "latency": null}
+		]`,
+	)
+	defer record.Release()
+
+	aggregations := []Aggregation{
+		Sum("amount"), Count(CountAllColumns), Count("amount"), Min("ts"), Max("ts"), Mean("latency"), CountDistinct("amount"),
+	}
+	expectedRows := []string{
+		"(null),6,2,2,2,7,4,2",
+		"u1,40,3,2,1,9,3,2",
+		"u2,40,2,2,3,8,2,1",
+	}
+
+	// split the record into three batches at every possible position, the sorted
+	// users are null, null, u1, u1, u1, u2, u2 so some groups span every batch
+	numRows := int(record.NumRows())
+	for split1 := 0; split1 <= numRows; split1++ {
+		for split2 := split1; split2 <= numRows; split2++ {
+			t.Run(fmt.Sprintf("split_%d_%d", split1, split2), func(t *testing.T) {
+				aggregator, err := NewSortedAggregator(mem, record.Schema(), []string{"user"}, aggregations...)
+				if err != nil {
+					t.Fatalf("received unexpected error: %s", err)
+				}
+				defer aggregator.Release()
+
+				actualRows := make([]string, 0)
+				bounds := []int{0, split1, split2, numRows}
+				for idx := 1; idx < len(bounds); idx++ {
+					batch := record.NewSlice(int64(bounds[idx-1]), int64(bounds[idx]))
+					defer batch.Release()
+
+					result, err := aggregator.Aggregate(batch)
+					if err != nil {
+						t.Fatalf("received unexpected error: %s", err)
+					}
+					defer result.Release()
+					actualRows = append(actualRows, recordRowStrings(result)...)
+				}
+				result, err := aggregator.Finish()
+				if err != nil {
+					t.Fatalf("received unexpected error: %s", err)
+				}
+				defer result.Release()
+				actualRows = append(actualRows, recordRowStrings(result)...)
+
+				if !slices.Equal(actualRows, expectedRows) {
+					t.Errorf("expected rows %v, got %v", expectedRows, actualRows)
+				}
+			})
+		}
+	}
+
+}
+
+func TestGroupByAggregateSorted(t *testing.T) {
+	mem := memory.NewGoAllocator()
+
+	record := recordFromJSON(t, mem, arrow.NewSchema(
+		[]arrow.Field{
+			{Name: "user", Type: arrow.BinaryTypes.String, Nullable: true},
+			{Name: "amount", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+			{Name: "ts", Type: arrow.PrimitiveTypes.Int32},
+			{Name: "latency", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+		}, nil), `[
+			{"user": null, "amount": 5, "ts": 7, "latency": null},
+			{"user": null, "amount": 1, "ts": 2, "latency": 4},
+			{"user": "u1", "amount": 10, "ts": 5, "latency": 1},
+			{"user": "u1", "amount": null, "ts": 1, "latency": 3},
+			{"user": "u1", "amount": 30, "ts": 9, "latency": 5},
+			{"user": "u2", "amount": 20, "ts": 3, "latency": 2},
+			{"user": "u2", "amount": 20, "ts": 8, "latency": null}
+		]`,
+	)
+	defer record.Release()
+
+	sortedResult, err := GroupBy(mem, record, []string{"user"}).AggregateSorted(Sum("amount"), Max("latency"))
+	if err != nil {
+		t.Fatalf("received unexpected error: %s", err)
+	}
+	defer sortedResult.Release()
+	hashResult, err := GroupBy(mem, record, []string{"user"}).Aggregate(Sum("amount"), Max("latency"))
+	if err != nil {
+		t.Fatalf("received unexpected error: %s", err)
+	}
+	defer hashResult.Release()
+
+	equal, err := RecordsEqualUnordered(sortedResult, hashResult)
+	if err != nil {
+		t.Fatalf("received unexpected error: %s", err)
+	}
+	if !equal {
+		t.Errorf("expected the sorted aggregation %v to equal the hash aggregation %v", sortedResult, hashResult)
+	}
+
+	// an empty record produces an empty result
+	empty := record.NewSlice(0, 0)
+	defer empty.Release()
+	emptyResult, err := GroupBy(mem, empty, []string{"user"}).AggregateSorted(Sum("amount"))
+	if err != nil {
+		t.Fatalf("received unexpected error: %s", err)
+	}
+	defer emptyResult.Release()
+	if emptyResult.NumRows() != 0 || emptyResult.NumCols() != 2 {
+		t.Errorf("expected an empty result with 2 columns, got %v", emptyResult)
+	}
+}
+
+func TestSortedAggregatorNotSorted(t *testing.T) {
+	mem := memory.NewGoAllocator()
+
+	schema := arrow.NewSchema([]arrow.Field{{Name: "user", Type: arrow.BinaryTypes.String, Nullable: true}}, nil)
+	record := recordFromJSON(t, mem, schema, `[{"user": "u2"}, {"user": "u1"}]`)
+	defer record.Release()
+
+	_, err := GroupBy(mem, record, []string{"user"}).AggregateSorted(Count(CountAllColumns))
+	if !errors.Is(err, ErrNotSorted) {
+		t.Errorf("expected error %v, got %v", ErrNotSorted, err)
+	}
+
+	// the second record starts before the end of the first record
+	sortedRecord := recordFromJSON(t, mem, schema, `[{"user": null}, {"user": "u1"}, {"user": "u2"}]`)
+	defer sortedRecord.Release()
+	aggregator, err := NewSortedAggregator(mem, sortedRecord.Schema(), []string{"user"}, Count(CountAllColumns))
+	if err != nil {
+		t.Fatalf("received unexpected error: %s", err)
+	}
+	defer aggregator.Release()
+
+	result, err := aggregator.Aggregate(sortedRecord)
+	if err != nil {
+		t.Fatalf("received unexpected error: %s", err)
+	}
+	defer result.Release()
+	_, err = aggregator.Aggregate(sortedRecord)
+	if !errors.Is(err, ErrNotSorted) {
+		t.Errorf("expected error %v, got %v", ErrNotSorted, err)
+	}
+}
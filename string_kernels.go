@@ -0,0 +1,328 @@
+package arrowops
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/alekLukanen/errs"
+	"github.com/apache/arrow/go/v17/arrow"
+	"github.com/apache/arrow/go/v17/arrow/array"
+	"github.com/apache/arrow/go/v17/arrow/memory"
+)
+
+/*
+Converts each value of a STRING or LARGE_STRING array to lower case. Like
+the other string kernels the result is null where the value is null and
+string results have the same type as the array.
+*/
+func StringLower(mem *memory.GoAllocator, arr arrow.Array) (arrow.Array, error) {
+	return mapStrings(mem, arr, func(s string) (string, bool) {
+		return strings.ToLower(s), true
+	})
+}
+
+/*
+Converts each value of a string array to upper case.
+*/
+func StringUpper(mem *memory.GoAllocator, arr arrow.Array) (arrow.Array, error) {
+	return mapStrings(mem, arr, func(s string) (string, bool) {
+		return strings.ToUpper(s), true
+	})
+}
+
+/*
+Removes leading and trailing white space from each value of a string array.
+*/
+func StringTrim(mem *memory.GoAllocator, arr arrow.Array) (arrow.Array, error) {
+	return mapStrings(mem, arr, func(s string) (string, bool) {
+		return strings.TrimSpace(s), true
+	})
+}
+
+/*
+Takes up to length characters from each value of a string array starting at
+the character at index start. A negative start counts back from the end of the
+value and a negative length takes every character after start.
+*/
+func StringSubstring(mem *memory.GoAllocator, arr arrow.Array, start, length int) (arrow.Array, error) {
+	return mapStrings(mem, arr, func(s string) (string, bool) {
+		numChars := utf8.RuneCountInString(s)
+		from := start
+		if from < 0 {
+			from = max(numChars+from, 0)
+		}
+		from = min(from, numChars)
+		to := numChars
+		if length >= 0 {
+			to = min(from+length, numChars)
+		}
+
+		// convert the character positions to byte offsets
+		fromByte, toByte := len(s), len(s)
+		char := 0
+		for offset := range s {
+			if char == from {
+				fromByte = offset
+			}
+			if char == to {
+				toByte = offset
+				break
+			}
+			char++
+		}
+		return s[fromByte:toByte], true
+	})
+}
+
+/*
+The number of characters in each value of a string array, as an INT32 for
+STRING arrays and an INT64 for LARGE_STRING arrays.
+*/
+func StringLength(mem *memory.GoAllocator, arr arrow.Array) (arrow.Array, error) {
+	if !isStringType(arr.DataType()) {
+		return nil, errs.NewStackError(fmt.Errorf("%w| expected a string array but got %s", ErrUnsupportedDataType, arr.DataType()))
+	}
+
+	if arr.DataType().ID() == arrow.LARGE_STRING {
+		b := array.NewInt64Builder(mem)
+		defer b.Release()
+		b.Reserve(arr.Len())
+		for i := 0; i < arr.Len(); i++ {
+			if arr.IsNull(i) {
+				b.AppendNull()
+				continue
+			}
+			s, _ := stringArrayValue(arr, i)
+			b.Append(int64(utf8.RuneCountInString(s)))
+		}
+		return b.NewArray(), nil
+	}
+
+	b := array.NewInt32Builder(mem)
+	defer b.Release()
+	b.Reserve(arr.Len())
+	for i := 0; i < arr.Len(); i++ {
+		if arr.IsNull(i) {
+			b.AppendNull()
+			continue
+		}
+		s, _ := stringArrayValue(arr, i)
+		b.Append(int32(utf8.RuneCountInString(s)))
+	}
+	return b.NewArray(), nil
+}
+
+/*
+Whether each value of a string array starts with the prefix.
+*/
+func StringStartsWith(mem *memory.GoAllocator, arr arrow.Array, prefix string) (*array.Boolean, error) {
+	return matchStrings(mem, arr, func(s string) bool {
+		return strings.HasPrefix(s, prefix)
+	})
+}
+
+/*
+Whether each value of a string array ends with the suffix.
+*/
+func StringEndsWith(mem *memory.GoAllocator, arr arrow.Array, suffix string) (*array.Boolean, error) {
+	return matchStrings(mem, arr, func(s string) bool {
+		return strings.HasSuffix(s, suffix)
+	})
+}
+
+/*
+Whether each value of a string array contains the substring.
+*/
+func StringContains(mem *memory.GoAllocator, arr arrow.Array, substring string) (*array.Boolean, error) {
+	return matchStrings(mem, arr, func(s string) bool {
+		return strings.Contains(s, substring)
+	})
+}
+
+/*
+Whether each value of a string array contains a match of the regular
+expression, which uses the syntax of the regexp package.
+*/
+func StringRegexMatch(mem *memory.GoAllocator, arr arrow.Array, pattern string) (*array.Boolean, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, errs.Wrap(err, fmt.Errorf("failed to compile pattern %q", pattern))
+	}
+	return matchStrings(mem, arr, re.MatchString)
+}
+
+/*
+Extracts the text matched by the capture group of the regular expression from
+each value of a string array. Group 0 is the whole match. The result is null
+where the value does not match or the group is not part of the match. Returns
+ErrIndexOutOfBounds if the pattern does not have the group.
+*/
+func StringRegexExtract(mem *memory.GoAllocator, arr arrow.Array, pattern string, group int) (arrow.Array, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, errs.Wrap(err, fmt.Errorf("failed to compile pattern %q", pattern))
+	}
+	if group < 0 || group > re.NumSubexp() {
+		return nil, errs.NewStackError(fmt.Errorf("%w| pattern %q has %d groups but group %d was requested", ErrIndexOutOfBounds, pattern, re.NumSubexp(), group))
+	}
+	return mapStrings(mem, arr, func(s string) (string, bool) {
+		match := re.FindStringSubmatchIndex(s)
+		if match == nil || match[2*group] < 0 {
+			return "", false
+		}
+		return s[match[2*group]:match[2*group+1]], true
+	})
+}
+
+/*
+Replaces every match of the regular expression in each value of a string array
+with the replacement, which can reference capture groups as $1 or ${name} like
+regexp.ReplaceAllString.
+*/
+func StringRegexReplace(mem *memory.GoAllocator, arr arrow.Array, pattern, replacement string) (arrow.Array, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, errs.Wrap(err, fmt.Errorf("failed to compile pattern %q", pattern))
+	}
+	return mapStrings(mem, arr, func(s string) (string, bool) {
+		return re.ReplaceAllString(s, replacement), true
+	})
+}
+
+/*
+Splits each value of a string array around each instance of the separator, like
+strings.Split, into a LIST of the array's string type, or a LARGE_LIST for
+LARGE_STRING arrays.
+*/
+func StringSplit(mem *memory.GoAllocator, arr arrow.Array, separator string) (arrow.Array, error) {
+	if !isStringType(arr.DataType()) {
+		return nil, errs.NewStackError(fmt.Errorf("%w| expected a string array but got %s", ErrUnsupportedDataType, arr.DataType()))
+	}
+
+	var b array.ListLikeBuilder
+	if arr.DataType().ID() == arrow.LARGE_STRING {
+		b = array.NewLargeListBuilder(mem, arr.DataType())
+	} else {
+		b = array.NewListBuilder(mem, arr.DataType())
+	}
+	defer b.Release()
+	b.Reserve(arr.Len())
+	valueBuilder := b.ValueBuilder()
+	for i := 0; i < arr.Len(); i++ {
+		if arr.IsNull(i) {
+			b.AppendNull()
+			continue
+		}
+		s, _ := stringArrayValue(arr, i)
+		parts := strings.Split(s, separator)
+		b.Append(true)
+		for _, part := range parts {
+			appendString(valueBuilder, part)
+		}
+	}
+	return b.NewArray(), nil
+}
+
+/*
+Joins the values in each row of the string arrays with the separator. The
+arrays must have the same length and the result is null where any of the
+values is null. The result is a LARGE_STRING array when any of the arrays
+is one, otherwise it is a STRING array.
+*/
+func StringConcat(mem *memory.GoAllocator, separator string, arrays ...arrow.Array) (arrow.Array, error) {
+	if len(arrays) == 0 {
+		return nil, errs.NewStackError(ErrNoDataSupplied)
+	}
+	resultType := arrow.BinaryTypes.String
+	for _, arr := range arrays {
+		if !isStringType(arr.DataType()) {
+			return nil, errs.NewStackError(fmt.Errorf("%w| expected a string array but got %s", ErrUnsupportedDataType, arr.DataType()))
+		}
+		if arr.Len() != arrays[0].Len() {
+			return nil, errs.NewStackError(fmt.Errorf("%w| arrays have %d and %d values", ErrLengthsNotEqual, arrays[0].Len(), arr.Len()))
+		}
+		if arr.DataType().ID() == arrow.LARGE_STRING {
+			resultType = arrow.BinaryTypes.LargeString
+		}
+	}
+
+	b := array.NewBuilder(mem, resultType)
+	defer b.Release()
+	b.Reserve(arrays[0].Len())
+	values := make([]string, len(arrays))
+	for i := 0; i < arrays[0].Len(); i++ {
+		isNull := false
+		for j, arr := range arrays {
+			if arr.IsNull(i) {
+				isNull = true
+				break
+			}
+			values[j], _ = stringArrayValue(arr, i)
+		}
+		if isNull {
+			b.AppendNull()
+			continue
+		}
+		appendString(b, strings.Join(values, separator))
+	}
+	return b.NewArray(), nil
+}
+
+/*
+Applies the function to each value of a string array and returns an array of
+the same type, the result is null where the value is null or the function
+returns false.
+*/
+func mapStrings(mem *memory.GoAllocator, arr arrow.Array, fn func(s string) (string, bool)) (arrow.Array, error) {
+	if !isStringType(arr.DataType()) {
+		return nil, errs.NewStackError(fmt.Errorf("%w| expected a string array but got %s", ErrUnsupportedDataType, arr.DataType()))
+	}
+
+	b := array.NewBuilder(mem, arr.DataType())
+	defer b.Release()
+	b.Reserve(arr.Len())
+	for i := 0; i < arr.Len(); i++ {
+		if arr.IsNull(i) {
+			b.AppendNull()
+			continue
+		}
+		s, _ := stringArrayValue(arr, i)
+		result, ok := fn(s)
+		if !ok {
+			b.AppendNull()
+			continue
+		}
+		appendString(b, result)
+	}
+	return b.NewArray(), nil
+}
+
+func matchStrings(mem *memory.GoAllocator, arr arrow.Array, fn func(s string) bool) (*array.Boolean, error) {
+	if !isStringType(arr.DataType()) {
+		return nil, errs.NewStackError(fmt.Errorf("%w| expected a string array but got %s", ErrUnsupportedDataType, arr.DataType()))
+	}
+
+	b := array.NewBooleanBuilder(mem)
+	defer b.Release()
+	b.Reserve(arr.Len())
+	for i := 0; i < arr.Len(); i++ {
+		if arr.IsNull(i) {
+			b.AppendNull()
+			continue
+		}
+		s, _ := stringArrayValue(arr, i)
+		b.Append(fn(s))
+	}
+	return b.NewBooleanArray(), nil
+}
+
+func appendString(b array.Builder, s string) {
+	switch b := b.(type) {
+	case *array.StringBuilder:
+		b.Append(s)
+	case *array.LargeStringBuilder:
+		b.Append(s)
+	}
+}
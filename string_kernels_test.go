@@ -0,0 +1,228 @@
+package arrowops
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/apache/arrow/go/v17/arrow"
+	"github.com/apache/arrow/go/v17/arrow/memory"
+)
+
+func TestStringKernels(t *testing.T) {
+
+	mem := memory.NewGoAllocator()
+
+	values := `["Hello World", null, "  ça va  ", ""]`
+
+	testCases := []struct {
+		caseName       string
+		compute        func(arr arrow.Array) (arrow.Array, error)
+		expectedType   arrow.DataType
+		expectedValues []string
+	}{
+		{
+			caseName: "lower",
+			compute: func(arr arrow.Array) (arrow.Array, error) {
+				return StringLower(mem, arr)
+			},
+			expectedValues: []string{"hello world", "(null)", "  ça va  ", ""},
+		},
+		{
+			caseName: "upper",
+			compute: func(arr arrow.Array) (arrow.Array, error) {
+				return StringUpper(mem, arr)
+			},
+			expectedValues: []string{"HELLO WORLD", "(null)", "  ÇA VA  ", ""},
+		},
+		{
+			caseName: "trim",
+			compute: func(arr arrow.Array) (arrow.Array, error) {
+				return StringTrim(mem, arr)
+			},
+			expectedValues: []string{"Hello World", "(null)", "ça va", ""},
+		},
+		{
+			caseName: "substring",
+			compute: func(arr arrow.Array) (arrow.Array, error) {
+				return StringSubstring(mem, arr, 2, 3)
+			},
+			expectedValues: []string{"llo", "(null)", "ça ", ""},
+		},
+		{
+			caseName: "substring_from_end",
+			compute: func(arr arrow.Array) (arrow.Array, error) {
+				return StringSubstring(mem, arr, -5, -1)
+			},
+			expectedValues: []string{"World", "(null)", " va  ", ""},
+		},
+		{
+			caseName: "length",
+			compute: func(arr arrow.Array) (arrow.Array, error) {
+				return StringLength(mem, arr)
+			},
+			expectedType:   arrow.PrimitiveTypes.Int32,
+			expectedValues: []string{"11", "(null)", "9", "0"},
+		},
+		{
+			caseName: "starts_with",
+			compute: func(arr arrow.Array) (arrow.Array, error) {
+				return StringStartsWith(mem, arr, "Hello")
+			},
+			expectedType:   arrow.FixedWidthTypes.Boolean,
+			expectedValues: []string{"true", "(null)", "false", "false"},
+		},
+		{
+			caseName: "ends_with",
+			compute: func(arr arrow.Array) (arrow.Array, error) {
+				return StringEndsWith(mem, arr, "  ")
+			},
+			expectedType:   arrow.FixedWidthTypes.Boolean,
+			expectedValues: []string{"false", "(null)", "true", "false"},
+		},
+		{
+			caseName: "contains",
+			compute: func(arr arrow.Array) (arrow.Array, error) {
+				return StringContains(mem, arr, "o W")
+			},
+			expectedType:   arrow.FixedWidthTypes.Boolean,
+			expectedValues: []string{"true", "(null)", "false", "false"},
+		},
+		{
+			caseName: "regex_match",
+			compute: func(arr arrow.Array) (arrow.Array, error) {
+				return StringRegexMatch(mem, arr, `^\s*ç`)
+			},
+			expectedType:   arrow.FixedWidthTypes.Boolean,
+			expectedValues: []string{"false", "(null)", "true", "false"},
+		},
+		{
+			caseName: "regex_extract",
+			compute: func(arr arrow.Array) (arrow.Array, error) {
+				// \w only matches ASCII characters
+				return StringRegexExtract(mem, arr, `(\w+) (\w+)`, 2)
+			},
+			expectedValues: []string{"World", "(null)", "va", "(null)"},
+		},
+		{
+			caseName: "regex_replace",
+			compute: func(arr arrow.Array) (arrow.Array, error) {
+				return StringRegexReplace(mem, arr, `(\w+) (\w+)`, "$2 $1")
+			},
+			expectedValues: []string{"World Hello", "(null)", "  çva a  ", ""},
+		},
+		{
+			caseName: "split",
+			compute: func(arr arrow.Array) (arrow.Array, error) {
+				return StringSplit(mem, arr, " ")
+			},
+			expectedType:   arrow.ListOf(arrow.BinaryTypes.String),
+			expectedValues: []string{`["Hello","World"]`, "(null)", `["","","ça","va","",""]`, `[""]`},
+		},
+		{
+			caseName: "concat",
+			compute: func(arr arrow.Array) (arrow.Array, error) {
+				return StringConcat(mem, "|", arr, arr)
+			},
+			expectedValues: []string{"Hello World|Hello World", "(null)", "  ça va  |  ça va  ", "|"},
+		},
+	}
+
+	for idx, tc := range testCases {
+		t.Run(fmt.Sprintf("case_%d:%s", idx, tc.caseName), func(t *testing.T) {
+			arr := castTestArray(t, mem, arrow.BinaryTypes.String, values)
+			defer arr.Release()
+
+			result, err := tc.compute(arr)
+			if err != nil {
+				t.Fatalf("received unexpected error: %s", err)
+			}
+			defer result.Release()
+
+			expectedType := tc.expectedType
+			if expectedType == nil {
+				expectedType = arrow.BinaryTypes.String
+			}
+			if !arrow.TypeEqual(result.DataType(), expectedType) {
+				t.Errorf("expected type %s, got %s", expectedType, result.DataType())
+			}
+			if actual := arrayValueStrings(result); !slices.Equal(actual, tc.expectedValues) {
+				t.Errorf("expected values %v, got %v", tc.expectedValues, actual)
+			}
+		})
+	}
+
+}
+
+func TestStringKernelsLargeString(t *testing.T) {
+	mem := memory.NewGoAllocator()
+
+	arr := castTestArray(t, mem, arrow.BinaryTypes.LargeString, `["a,b", null]`)
+	defer arr.Release()
+
+	upper, err := StringUpper(mem, arr)
+	if err != nil {
+		t.Fatalf("received unexpected error: %s", err)
+	}
+	defer upper.Release()
+	if !arrow.TypeEqual(upper.DataType(), arrow.BinaryTypes.LargeString) {
+		t.Errorf("expected type %s, got %s", arrow.BinaryTypes.LargeString, upper.DataType())
+	}
+
+	length, err := StringLength(mem, arr)
+	if err != nil {
+		t.Fatalf("received unexpected error: %s", err)
+	}
+	defer length.Release()
+	if !arrow.TypeEqual(length.DataType(), arrow.PrimitiveTypes.Int64) {
+		t.Errorf("expected type %s, got %s", arrow.PrimitiveTypes.Int64, length.DataType())
+	}
+
+	split, err := StringSplit(mem, arr, ",")
+	if err != nil {
+		t.Fatalf("received unexpected error: %s", err)
+	}
+	defer split.Release()
+	if !arrow.TypeEqual(split.DataType(), arrow.LargeListOf(arrow.BinaryTypes.LargeString)) {
+		t.Errorf("expected type %s, got %s", arrow.LargeListOf(arrow.BinaryTypes.LargeString), split.DataType())
+	}
+
+	other := castTestArray(t, mem, arrow.BinaryTypes.String, `["c", "d"]`)
+	defer other.Release()
+	concat, err := StringConcat(mem, "", other, arr)
+	if err != nil {
+		t.Fatalf("received unexpected error: %s", err)
+	}
+	defer concat.Release()
+	expectedValues := []string{"ca,b", "(null)"}
+	if actual := arrayValueStrings(concat); !slices.Equal(actual, expectedValues) {
+		t.Errorf("expected values %v, got %v", expectedValues, actual)
+	}
+}
+
+func TestStringKernelsErrors(t *testing.T) {
+	mem := memory.NewGoAllocator()
+
+	strs := castTestArray(t, mem, arrow.BinaryTypes.String, `["a"]`)
+	defer strs.Release()
+	ints := castTestArray(t, mem, arrow.PrimitiveTypes.Int32, "[1]")
+	defer ints.Release()
+
+	_, err := StringLower(mem, ints)
+	if !errors.Is(err, ErrUnsupportedDataType) {
+		t.Errorf("expected error %v, got %v", ErrUnsupportedDataType, err)
+	}
+	_, err = StringRegexExtract(mem, strs, `(a)`, 2)
+	if !errors.Is(err, ErrIndexOutOfBounds) {
+		t.Errorf("expected error %v, got %v", ErrIndexOutOfBounds, err)
+	}
+	_, err = StringRegexMatch(mem, strs, `(`)
+	if err == nil {
+		t.Errorf("expected an error for an invalid pattern")
+	}
+	_, err = StringConcat(mem, "")
+	if !errors.Is(err, ErrNoDataSupplied) {
+		t.Errorf("expected error %v, got %v", ErrNoDataSupplied, err)
+	}
+}
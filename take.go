@@ -12,7 +12,8 @@ import (
 
 /*
 Take all rows from the input record based on the input indices array.
-The resulting record contains data copied from the original record.
+The resulting record contains data copied from the original record,
+including null values.
 */
 func TakeRecord(mem *memory.GoAllocator, record arrow.Record, indices *array.Uint32) (arrow.Record, error) {
 	record.Retain()
@@ -37,6 +38,23 @@ func TakeRecord(mem *memory.GoAllocator, record arrow.Record, indices *array.Uin
 	return array.NewRecord(record.Schema(), takenFields, int64(indices.Len())), nil
 }
 
+/*
+Same as TakeRecord with the row indices provided as a slice.
+*/
+func takeRecordRows(mem *memory.GoAllocator, record arrow.Record, rows []uint32) (arrow.Record, error) {
+	indicesBuilder := array.NewUint32Builder(mem)
+	defer indicesBuilder.Release()
+	indicesBuilder.AppendValues(rows, nil)
+	indices := indicesBuilder.NewUint32Array()
+	defer indices.Release()
+
+	return TakeRecord(mem, record, indices)
+}
+
+/*
+Take the values from the input array based on the input indices array.
+A null index produces a null value in the resulting array.
+*/
 func TakeArray(mem *memory.GoAllocator, arr arrow.Array, indices *array.Uint32) (arrow.Array, error) {
 	switch arr.DataType().ID() {
 	case arrow.BOOL:
@@ -65,8 +83,10 @@ func TakeArray(mem *memory.GoAllocator, arr arrow.Array, indices *array.Uint32)
 		return takeNativeArray[float64, *array.Float64](array.NewFloat64Builder(mem), arr.(*array.Float64), indices)
 	case arrow.STRING:
 		return takeNativeArray[string, *array.String](array.NewStringBuilder(mem), arr.(*array.String), indices)
-	case arrow.BINARY:
-		return takeBinaryArray(mem, arr.(*array.Binary), indices)
+	case arrow.LARGE_STRING:
+		return takeNativeArray[string, *array.LargeString](array.NewLargeStringBuilder(mem), arr.(*array.LargeString), indices)
+	case arrow.BINARY, arrow.LARGE_BINARY:
+		return takeBinaryArray(mem, arr.(binaryValueArray), indices)
 	case arrow.DATE32:
 		return takeNativeArray[arrow.Date32, *array.Date32](array.NewDate32Builder(mem), arr.(*array.Date32), indices)
 	case arrow.DATE64:
@@ -98,10 +118,18 @@ func takeBoolArray(mem *memory.GoAllocator, arr *array.Boolean, indices *array.U
 	arrLen := arr.Len()
 	b.Reserve(indices.Len())
 	for i := 0; i < indices.Len(); i++ {
+		if indices.IsNull(i) {
+			b.AppendNull()
+			continue
+		}
 		idx := int(indices.Value(i))
 		if idx >= arrLen || idx < 0 {
 			return nil, ErrIndexOutOfBounds
 		}
+		if arr.IsNull(idx) {
+			b.AppendNull()
+			continue
+		}
 		b.Append(arr.Value(idx))
 	}
 	return b.NewBooleanArray(), nil
@@ -112,26 +140,42 @@ func takeNativeArray[T comparable, E valueArray[T]](b arrayBuilder[T], arr E, in
 	arrLen := arr.Len()
 	b.Reserve(indices.Len())
 	for i := 0; i < indices.Len(); i++ {
+		if indices.IsNull(i) {
+			b.AppendNull()
+			continue
+		}
 		idx := int(indices.Value(i))
 		if idx >= arrLen || idx < 0 {
 			return *new(E), fmt.Errorf("%w| record index out of bounds", ErrIndexOutOfBounds)
 		}
+		if arr.IsNull(idx) {
+			b.AppendNull()
+			continue
+		}
 		b.Append(arr.Value(idx))
 	}
 	return b.NewArray().(E), nil
 }
 
-func takeBinaryArray(mem *memory.GoAllocator, arr *array.Binary, indices *array.Uint32) (*array.Binary, error) {
-	b := array.NewBinaryBuilder(mem, arrow.BinaryTypes.Binary)
+func takeBinaryArray(mem *memory.GoAllocator, arr binaryValueArray, indices *array.Uint32) (arrow.Array, error) {
+	b := array.NewBinaryBuilder(mem, arr.DataType().(arrow.BinaryDataType))
 	defer b.Release()
 	arrLen := arr.Len()
 	b.Reserve(indices.Len())
 	for i := 0; i < indices.Len(); i++ {
+		if indices.IsNull(i) {
+			b.AppendNull()
+			continue
+		}
 		idx := int(indices.Value(i))
 		if idx >= arrLen || idx < 0 {
 			return nil, ErrIndexOutOfBounds
 		}
+		if arr.IsNull(idx) {
+			b.AppendNull()
+			continue
+		}
 		b.Append(arr.Value(idx))
 	}
-	return b.NewBinaryArray(), nil
+	return b.NewArray(), nil
 }
@@ -102,6 +102,10 @@ func TakeMultipleRecords(mem *memory.GoAllocator, records []arrow.Record, indice
 	return resultRecord, nil
 }
 
+/*
+Take the values from the input arrays based on the input indices record, in the same
+way as TakeMultipleRecords. A null index produces a null value in the resulting array.
+*/
 func TakeMultipleArrays(mem *memory.GoAllocator, arrs []arrow.Array, indices arrow.Record) (arrow.Array, error) {
 	switch arrs[0].DataType().ID() {
 	case arrow.BOOL:
@@ -131,7 +135,9 @@ func TakeMultipleArrays(mem *memory.GoAllocator, arrs []arrow.Array, indices arr
 		return takeNativeArrays[float64, *array.Float64](array.NewFloat64Builder(mem), arrs, indices)
 	case arrow.STRING:
 		return takeNativeArrays[string, *array.String](array.NewStringBuilder(mem), arrs, indices)
-	case arrow.BINARY:
+	case arrow.LARGE_STRING:
+		return takeNativeArrays[string, *array.LargeString](array.NewLargeStringBuilder(mem), arrs, indices)
+	case arrow.BINARY, arrow.LARGE_BINARY:
 		return takeBinaryArrays(mem, arrs, indices)
 	case arrow.DATE32:
 		return takeNativeArrays[arrow.Date32, *array.Date32](array.NewDate32Builder(mem), arrs, indices)
@@ -172,8 +178,16 @@ func takeBoolArrays(mem *memory.GoAllocator, arr []arrow.Array, indices arrow.Re
 	defer b.Release()
 	b.Reserve(int(indices.NumRows()))
 	for i := 0; i < int(indices.NumRows()); i++ {
+		if recordSliceIndices.IsNull(i) || recordIndices.IsNull(i) {
+			b.AppendNull()
+			continue
+		}
 		recIdx := int(recordSliceIndices.Value(i))
 		rowIdx := int(recordIndices.Value(i))
+		if booleanArrays[recIdx].IsNull(rowIdx) {
+			b.AppendNull()
+			continue
+		}
 		b.Append(booleanArrays[recIdx].Value(rowIdx))
 	}
 
@@ -193,29 +207,45 @@ func takeNativeArrays[T comparable, E valueArray[T]](b arrayBuilder[T], arrs []a
 
 	b.Reserve(int(indices.NumRows()))
 	for i := 0; i < int(indices.NumRows()); i++ {
+		if recordSliceIndices.IsNull(i) || recordIndices.IsNull(i) {
+			b.AppendNull()
+			continue
+		}
 		recIdx := int(recordSliceIndices.Value(i))
 		rowIdx := int(recordIndices.Value(i))
+		if EArrays[recIdx].IsNull(rowIdx) {
+			b.AppendNull()
+			continue
+		}
 		b.Append(EArrays[recIdx].Value(rowIdx))
 	}
 	return b.NewArray().(E), nil
 }
 
-func takeBinaryArrays(mem *memory.GoAllocator, arr []arrow.Array, indices arrow.Record) (*array.Binary, error) {
-	binaryArrays := make([]*array.Binary, len(arr))
+func takeBinaryArrays(mem *memory.GoAllocator, arr []arrow.Array, indices arrow.Record) (arrow.Array, error) {
+	binaryArrays := make([]binaryValueArray, len(arr))
 	for idx, a := range arr {
-		binaryArrays[idx] = a.(*array.Binary)
+		binaryArrays[idx] = a.(binaryValueArray)
 	}
 
 	recordSliceIndices := indices.Column(0).(*array.Uint32)
 	recordIndices := indices.Column(1).(*array.Uint32)
 
-	b := array.NewBinaryBuilder(mem, arrow.BinaryTypes.Binary)
+	b := array.NewBinaryBuilder(mem, arr[0].DataType().(arrow.BinaryDataType))
 	defer b.Release()
 	b.Reserve(int(indices.NumRows()))
 	for i := 0; i < int(indices.NumRows()); i++ {
+		if recordSliceIndices.IsNull(i) || recordIndices.IsNull(i) {
+			b.AppendNull()
+			continue
+		}
 		recIdx := int(recordSliceIndices.Value(i))
 		rowIdx := int(recordIndices.Value(i))
+		if binaryArrays[recIdx].IsNull(rowIdx) {
+			b.AppendNull()
+			continue
+		}
 		b.Append(binaryArrays[recIdx].Value(rowIdx))
 	}
-	return b.NewBinaryArray(), nil
+	return b.NewArray(), nil
 }
@@ -2,6 +2,7 @@ package arrowops
 
 import (
 	"fmt"
+	"slices"
 	"testing"
 
 	"github.com/apache/arrow/go/v17/arrow"
@@ -103,3 +104,103 @@ func TestTakeRecord(t *testing.T) {
 	}
 
 }
+
+func TestTakeRecordWithNullValues(t *testing.T) {
+	mem := memory.NewGoAllocator()
+
+	schema := arrow.NewSchema(
+		[]arrow.Field{
+			{Name: "a", Type: arrow.PrimitiveTypes.Uint32, Nullable: true},
+			{Name: "b", Type: arrow.FixedWidthTypes.Boolean, Nullable: true},
+			{Name: "c", Type: arrow.BinaryTypes.Binary, Nullable: true},
+		},
+		nil,
+	)
+
+	rb1 := array.NewRecordBuilder(mem, schema)
+	defer rb1.Release()
+	rb1.Field(0).(*array.Uint32Builder).AppendValues([]uint32{1, 2, 3}, []bool{true, false, true})
+	rb1.Field(1).(*array.BooleanBuilder).AppendValues([]bool{true, true, false}, []bool{false, true, true})
+	rb1.Field(2).(*array.BinaryBuilder).AppendValues([][]byte{[]byte("s1"), []byte("s2"), []byte("s3")}, []bool{true, true, false})
+	record := rb1.NewRecord()
+	defer record.Release()
+
+	rb2 := array.NewRecordBuilder(mem, schema)
+	defer rb2.Release()
+	rb2.Field(0).(*array.Uint32Builder).AppendValues([]uint32{3, 0, 1}, []bool{true, false, true})
+	rb2.Field(1).(*array.BooleanBuilder).AppendValues([]bool{false, true, false}, []bool{true, true, false})
+	rb2.Field(2).(*array.BinaryBuilder).AppendValues([][]byte{nil, []byte("s2"), []byte("s1")}, []bool{false, true, true})
+	expectedRecord := rb2.NewRecord()
+	defer expectedRecord.Release()
+
+	indicesBuilder := array.NewUint32Builder(mem)
+	defer indicesBuilder.Release()
+	indicesBuilder.AppendValues([]uint32{2, 1, 0}, nil)
+	indices := indicesBuilder.NewUint32Array()
+	defer indices.Release()
+
+	takenRecord, err := TakeRecord(mem, record, indices)
+	if err != nil {
+		t.Fatalf("TakeRecord() error = %v, wantErr %v", err, nil)
+	}
+	defer takenRecord.Release()
+
+	if !array.RecordEqual(expectedRecord, takenRecord) {
+		t.Errorf("TakeRecord() = %v, want %v", takenRecord, expectedRecord)
+	}
+}
+
+func TestTakeLargeStringAndBinaryArrays(t *testing.T) {
+	mem := memory.NewGoAllocator()
+
+	largeStrings := array.NewLargeStringBuilder(mem)
+	defer largeStrings.Release()
+	largeStrings.AppendValues([]string{"s0", "", "s2"}, []bool{true, false, true})
+	largeBinaries := array.NewBinaryBuilder(mem, arrow.BinaryTypes.LargeBinary)
+	defer largeBinaries.Release()
+	largeBinaries.AppendValues([][]byte{[]byte("b0"), []byte("b1"), nil}, []bool{true, true, false})
+
+	arrays := []arrow.Array{largeStrings.NewArray(), largeBinaries.NewArray()}
+	defer releaseArrays(arrays)
+	expectedValues := [][]string{{"s2", "(null)", "s0"}, {"(null)", "YjE=", "YjA="}}
+
+	indicesBuilder := array.NewUint32Builder(mem)
+	defer indicesBuilder.Release()
+	indicesBuilder.AppendValues([]uint32{2, 1, 0}, nil)
+	indices := indicesBuilder.NewUint32Array()
+	defer indices.Release()
+
+	recordIndicesBuilder := array.NewRecordBuilder(mem, arrow.NewSchema(
+		[]arrow.Field{
+			{Name: "record", Type: arrow.PrimitiveTypes.Uint32},
+			{Name: "recordIdx", Type: arrow.PrimitiveTypes.Uint32},
+		}, nil))
+	defer recordIndicesBuilder.Release()
+	recordIndicesBuilder.Field(0).(*array.Uint32Builder).AppendValues([]uint32{0, 0, 0}, nil)
+	recordIndicesBuilder.Field(1).(*array.Uint32Builder).AppendValues([]uint32{2, 1, 0}, nil)
+	recordIndices := recordIndicesBuilder.NewRecord()
+	defer recordIndices.Release()
+
+	for idx, arr := range arrays {
+		taken, err := TakeArray(mem, arr, indices)
+		if err != nil {
+			t.Fatalf("TakeArray() error = %v, wantErr %v", err, nil)
+		}
+		defer taken.Release()
+		if !arrow.TypeEqual(taken.DataType(), arr.DataType()) {
+			t.Errorf("TakeArray() type = %s, want %s", taken.DataType(), arr.DataType())
+		}
+		if actual := arrayValueStrings(taken); !slices.Equal(actual, expectedValues[idx]) {
+			t.Errorf("TakeArray() = %v, want %v", actual, expectedValues[idx])
+		}
+
+		takenMultiple, err := TakeMultipleArrays(mem, []arrow.Array{arr}, recordIndices)
+		if err != nil {
+			t.Fatalf("TakeMultipleArrays() error = %v, wantErr %v", err, nil)
+		}
+		defer takenMultiple.Release()
+		if actual := arrayValueStrings(takenMultiple); !slices.Equal(actual, expectedValues[idx]) {
+			t.Errorf("TakeMultipleArrays() = %v, want %v", actual, expectedValues[idx])
+		}
+	}
+}
@@ -0,0 +1,361 @@
+package arrowops
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/alekLukanen/errs"
+	"github.com/apache/arrow/go/v17/arrow"
+	"github.com/apache/arrow/go/v17/arrow/array"
+	"github.com/apache/arrow/go/v17/arrow/memory"
+)
+
+type TemporalField int
+
+const (
+	YearField TemporalField = iota
+	MonthField
+	WeekField
+	DayField
+	WeekdayField
+	HourField
+	MinuteField
+	SecondField
+)
+
+func (f TemporalField) String() string {
+	switch f {
+	case YearField:
+		return "year"
+	case MonthField:
+		return "month"
+	case WeekField:
+		return "week"
+	case DayField:
+		return "day"
+	case WeekdayField:
+		return "weekday"
+	case HourField:
+		return "hour"
+	case MinuteField:
+		return "minute"
+	default:
+		return "second"
+	}
+}
+
+/*
+Truncates each value of a TIMESTAMP, DATE32 or DATE64 array to the start of
+its year, month, week, day, hour, minute or second. Weeks start on Monday.
+Timestamps are truncated in their time zone, or UTC when they do not have
+one, and dates are truncated in UTC. The result has the same type as the
+array.
+*/
+func TemporalTruncate(mem *memory.GoAllocator, arr arrow.Array, field TemporalField) (arrow.Array, error) {
+	if field == WeekdayField {
+		return nil, errs.NewStackError(fmt.Errorf("%w| can not truncate to the %s", ErrUnsupportedDataType, field))
+	}
+	converter, err := newInstantConverter(arr.DataType())
+	if err != nil {
+		return nil, err
+	}
+
+	b := array.NewBuilder(mem, arr.DataType())
+	defer b.Release()
+	b.Reserve(arr.Len())
+	for i := 0; i < arr.Len(); i++ {
+		if arr.IsNull(i) {
+			b.AppendNull()
+			continue
+		}
+		value, err := converter.fromTime(truncateTime(converter.toTime(instantArrayValue(arr, i)), field))
+		if err != nil {
+			return nil, errs.Wrap(err, fmt.Errorf("failed to truncate row %d to the %s", i, field))
+		}
+		appendInstant(b, value)
+	}
+	return b.NewArray(), nil
+}
+
+/*
+Extracts a field from each value of a TIMESTAMP, DATE32 or DATE64 array as an
+INT64, in the same time zone as TemporalTruncate. Months and days start at one,
+weeks are ISO 8601 week numbers and weekdays start at zero for Sunday like
+time.Weekday.
+*/
+func TemporalExtract(mem *memory.GoAllocator, arr arrow.Array, field TemporalField) (arrow.Array, error) {
+	converter, err := newInstantConverter(arr.DataType())
+	if err != nil {
+		return nil, err
+	}
+
+	b := array.NewInt64Builder(mem)
+	defer b.Release()
+	b.Reserve(arr.Len())
+	for i := 0; i < arr.Len(); i++ {
+		if arr.IsNull(i) {
+			b.AppendNull()
+			continue
+		}
+		t := converter.toTime(instantArrayValue(arr, i))
+		switch field {
+		case YearField:
+			b.Append(int64(t.Year()))
+		case MonthField:
+			b.Append(int64(t.Month()))
+		case WeekField:
+			_, week := t.ISOWeek()
+			b.Append(int64(week))
+		case DayField:
+			b.Append(int64(t.Day()))
+		case WeekdayField:
+			b.Append(int64(t.Weekday()))
+		case HourField:
+			b.Append(int64(t.Hour()))
+		case MinuteField:
+			b.Append(int64(t.Minute()))
+		default:
+			b.Append(int64(t.Second()))
+		}
+	}
+	return b.NewArray(), nil
+}
+
+/*
+Adds the values of a DURATION array to the values of a TIMESTAMP, DATE32 or
+DATE64 array with the same length. The result is a timestamp in the finer of
+the two units, with the time zone of the timestamps, and is null where either
+value is null. Dates are treated as midnight UTC. Overflow is handled in the
+same way as ArithmeticArrays.
+*/
+func TemporalAdd(mem *memory.GoAllocator, arr, durations arrow.Array, opts ArithmeticOptions) (arrow.Array, error) {
+	if arr.Len() != durations.Len() {
+		return nil, errs.NewStackError(fmt.Errorf("%w| array has %d values and durations has %d values", ErrLengthsNotEqual, arr.Len(), durations.Len()))
+	}
+	return temporalAdd(mem, exprValue{arr: arr}, exprValue{arr: durations}, arr.Len(), opts)
+}
+
+/*
+Same as TemporalAdd but adds the same duration to every value.
+*/
+func TemporalAddScalar(mem *memory.GoAllocator, arr arrow.Array, duration time.Duration, opts ArithmeticOptions) (arrow.Array, error) {
+	b := array.NewDurationBuilder(mem, arrow.FixedWidthTypes.Duration_ns.(*arrow.DurationType))
+	defer b.Release()
+	b.Append(arrow.Duration(duration))
+	durations := b.NewArray()
+	defer durations.Release()
+	return temporalAdd(mem, exprValue{arr: arr}, exprValue{arr: durations, scalar: true}, arr.Len(), opts)
+}
+
+/*
+Subtracts the values of the right array from the values of the left array,
+where both arrays are TIMESTAMP, DATE32 or DATE64 arrays with the same length.
+The result is a DURATION in the finer unit of the two arrays, with seconds for
+DATE32 and milliseconds for DATE64 values, and is null where either value is
+null. Overflow is handled in the same way as ArithmeticArrays.
+*/
+func TemporalDifference(mem *memory.GoAllocator, left, right arrow.Array, opts ArithmeticOptions) (arrow.Array, error) {
+	if left.Len() != right.Len() {
+		return nil, errs.NewStackError(fmt.Errorf("%w| left has %d values and right has %d values", ErrLengthsNotEqual, left.Len(), right.Len()))
+	}
+	if !isInstantType(left.DataType()) || !isInstantType(right.DataType()) {
+		return nil, errs.NewStackError(fmt.Errorf("%w| can not subtract %s from %s", ErrUnsupportedDataType, right.DataType(), left.DataType()))
+	}
+
+	unit := max(instantUnit(left.DataType()), instantUnit(right.DataType()))
+	b := array.NewDurationBuilder(mem, &arrow.DurationType{Unit: unit})
+	defer b.Release()
+	b.Reserve(left.Len())
+	for i := 0; i < left.Len(); i++ {
+		if left.IsNull(i) || right.IsNull(i) {
+			b.AppendNull()
+			continue
+		}
+		l, err := scaleInstant(instantArrayValue(left, i), instantNanosPerUnit(left.DataType()), unit, opts)
+		if err != nil {
+			return nil, errs.Wrap(err, fmt.Errorf("failed to compute row %d", i))
+		}
+		r, err := scaleInstant(instantArrayValue(right, i), instantNanosPerUnit(right.DataType()), unit, opts)
+		if err != nil {
+			return nil, errs.Wrap(err, fmt.Errorf("failed to compute row %d", i))
+		}
+		difference, err := applyNumericOperator(SubtractOperator, l, r, 64, opts)
+		if err != nil {
+			return nil, errs.Wrap(err, fmt.Errorf("failed to compute row %d", i))
+		}
+		b.Append(arrow.Duration(difference.i))
+	}
+	return b.NewArray(), nil
+}
+
+/*
+Changes the time zone of a TIMESTAMP array. The values are instants in UTC so
+only the type changes, which changes the local time used by TemporalTruncate
+and TemporalExtract.
+*/
+func TemporalToTimeZone(mem *memory.GoAllocator, arr arrow.Array, timeZone string) (arrow.Array, error) {
+	timestampType, ok := arr.DataType().(*arrow.TimestampType)
+	if !ok {
+		return nil, errs.NewStackError(fmt.Errorf("%w| expected a timestamp array but got %s", ErrUnsupportedDataType, arr.DataType()))
+	}
+	if _, err := time.LoadLocation(timeZone); err != nil {
+		return nil, errs.Wrap(err, fmt.Errorf("failed to load time zone %q", timeZone))
+	}
+	return CastArray(mem, arr, &arrow.TimestampType{Unit: timestampType.Unit, TimeZone: timeZone}, CastOptions{})
+}
+
+/*
+Converts a TIMESTAMP or DURATION array to another unit with CastArray, so
+converting to a coarser unit returns ErrLossyCast unless opts allows it.
+*/
+func TemporalToUnit(mem *memory.GoAllocator, arr arrow.Array, unit arrow.TimeUnit, opts CastOptions) (arrow.Array, error) {
+	switch dt := arr.DataType().(type) {
+	case *arrow.TimestampType:
+		return CastArray(mem, arr, &arrow.TimestampType{Unit: unit, TimeZone: dt.TimeZone}, opts)
+	case *arrow.DurationType:
+		return CastArray(mem, arr, &arrow.DurationType{Unit: unit}, opts)
+	default:
+		return nil, errs.NewStackError(fmt.Errorf("%w| expected a timestamp or duration array but got %s", ErrUnsupportedDataType, arr.DataType()))
+	}
+}
+
+func temporalAdd(mem *memory.GoAllocator, left, right exprValue, length int, opts ArithmeticOptions) (arrow.Array, error) {
+	durationType, ok := right.arr.DataType().(*arrow.DurationType)
+	if !ok || !isInstantType(left.arr.DataType()) {
+		return nil, errs.NewStackError(fmt.Errorf("%w| can not add %s to %s", ErrUnsupportedDataType, right.arr.DataType(), left.arr.DataType()))
+	}
+
+	resultType := &arrow.TimestampType{Unit: max(instantUnit(left.arr.DataType()), durationType.Unit)}
+	if timestampType, ok := left.arr.DataType().(*arrow.TimestampType); ok {
+		resultType.TimeZone = timestampType.TimeZone
+	}
+	leftNanos := instantNanosPerUnit(left.arr.DataType())
+	durationNanos := int64(durationType.Unit.Multiplier())
+
+	b := array.NewTimestampBuilder(mem, resultType)
+	defer b.Release()
+	b.Reserve(length)
+	durations := right.arr.(*array.Duration)
+	for row := 0; row < length; row++ {
+		if left.isNull(row) || right.isNull(row) {
+			b.AppendNull()
+			continue
+		}
+		l, err := scaleInstant(instantArrayValue(left.arr, left.index(row)), leftNanos, resultType.Unit, opts)
+		if err != nil {
+			return nil, errs.Wrap(err, fmt.Errorf("failed to compute row %d", row))
+		}
+		r, err := scaleInstant(int64(durations.Value(right.index(row))), durationNanos, resultType.Unit, opts)
+		if err != nil {
+			return nil, errs.Wrap(err, fmt.Errorf("failed to compute row %d", row))
+		}
+		sum, err := applyNumericOperator(AddOperator, l, r, 64, opts)
+		if err != nil {
+			return nil, errs.Wrap(err, fmt.Errorf("failed to compute row %d", row))
+		}
+		b.Append(arrow.Timestamp(sum.i))
+	}
+	return b.NewArray(), nil
+}
+
+/*
+The unit of a temporal type, seconds for Date32 and milliseconds for Date64.
+*/
+func instantUnit(dataType arrow.DataType) arrow.TimeUnit {
+	switch dt := dataType.(type) {
+	case *arrow.TimestampType:
+		return dt.Unit
+	case *arrow.Date32Type:
+		return arrow.Second
+	default:
+		return arrow.Millisecond
+	}
+}
+
+/*
+Converts a value to a unit that is at least as fine as its own unit.
+*/
+func scaleInstant(value, nanos int64, unit arrow.TimeUnit, opts ArithmeticOptions) (numericValue, error) {
+	factor := numericValue{kind: signedNumeric, i: nanos / int64(unit.Multiplier())}
+	return applyNumericOperator(MultiplyOperator, numericValue{kind: signedNumeric, i: value}, factor, 64, opts)
+}
+
+/*
+Converts the values of a temporal type to and from times in the type's time zone.
+*/
+type instantConverter struct {
+	toTime   func(value int64) time.Time
+	fromTime func(t time.Time) (int64, error)
+}
+
+func newInstantConverter(dataType arrow.DataType) (instantConverter, error) {
+	switch dt := dataType.(type) {
+	case *arrow.TimestampType:
+		location, err := dt.GetZone()
+		if err != nil {
+			return instantConverter{}, errs.Wrap(err, fmt.Errorf("failed to load time zone %q", dt.TimeZone))
+		}
+		return instantConverter{
+			toTime: func(value int64) time.Time {
+				return arrow.Timestamp(value).ToTime(dt.Unit).In(location)
+			},
+			fromTime: func(t time.Time) (int64, error) {
+				value, err := arrow.TimestampFromTime(t, dt.Unit)
+				return int64(value), err
+			},
+		}, nil
+	case *arrow.Date32Type:
+		return instantConverter{
+			toTime: func(value int64) time.Time {
+				return arrow.Date32(value).ToTime()
+			},
+			fromTime: func(t time.Time) (int64, error) {
+				return int64(arrow.Date32FromTime(t)), nil
+			},
+		}, nil
+	case *arrow.Date64Type:
+		return instantConverter{
+			toTime: func(value int64) time.Time {
+				return arrow.Date64(value).ToTime()
+			},
+			fromTime: func(t time.Time) (int64, error) {
+				return int64(arrow.Date64FromTime(t)), nil
+			},
+		}, nil
+	default:
+		return instantConverter{}, errs.NewStackError(fmt.Errorf("%w| expected a timestamp or date array but got %s", ErrUnsupportedDataType, dataType))
+	}
+}
+
+func truncateTime(t time.Time, field TemporalField) time.Time {
+	year, month, day := t.Date()
+	location := t.Location()
+	switch field {
+	case YearField:
+		return time.Date(year, time.January, 1, 0, 0, 0, 0, location)
+	case MonthField:
+		return time.Date(year, month, 1, 0, 0, 0, 0, location)
+	case WeekField:
+		daysSinceMonday := (int(t.Weekday()) + 6) % 7
+		return time.Date(year, month, day-daysSinceMonday, 0, 0, 0, 0, location)
+	case DayField:
+		return time.Date(year, month, day, 0, 0, 0, 0, location)
+	case HourField:
+		return time.Date(year, month, day, t.Hour(), 0, 0, 0, location)
+	case MinuteField:
+		return time.Date(year, month, day, t.Hour(), t.Minute(), 0, 0, location)
+	default:
+		return time.Date(year, month, day, t.Hour(), t.Minute(), t.Second(), 0, location)
+	}
+}
+
+func appendInstant(b array.Builder, value int64) {
+	switch b := b.(type) {
+	case *array.TimestampBuilder:
+		b.Append(arrow.Timestamp(value))
+	case *array.Date32Builder:
+		b.Append(arrow.Date32(value))
+	case *array.Date64Builder:
+		b.Append(arrow.Date64(value))
+	}
+}
@@ -0,0 +1,246 @@
+package arrowops
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow/go/v17/arrow"
+	"github.com/apache/arrow/go/v17/arrow/memory"
+)
+
+func TestTemporalTruncateAndExtract(t *testing.T) {
+
+	mem := memory.NewGoAllocator()
+
+	utc := &arrow.TimestampType{Unit: arrow.Second, TimeZone: "UTC"}
+	newYork := &arrow.TimestampType{Unit: arrow.Second, TimeZone: "America/New_York"}
+	// 2024-03-14 is a Thursday and 2024-03-15 02:30 UTC is 2024-03-14 22:30 in New York
+	values := `["2024-03-14T13:45:30Z", null, "2024-03-15T02:30:00Z"]`
+
+	testCases := []struct {
+		caseName       string
+		dataType       arrow.DataType
+		values         string
+		compute        func(arr arrow.Array) (arrow.Array, error)
+		expectedValues []string
+	}{
+		{
+			caseName: "truncate_to_hour",
+			dataType: utc,
+			compute: func(arr arrow.Array) (arrow.Array, error) {
+				return TemporalTruncate(mem, arr, HourField)
+			},
+			expectedValues: []string{"2024-03-14 13:00:00Z", "(null)", "2024-03-15 02:00:00Z"},
+		},
+		{
+			caseName: "truncate_to_week",
+			dataType: utc,
+			compute: func(arr arrow.Array) (arrow.Array, error) {
+				return TemporalTruncate(mem, arr, WeekField)
+			},
+			expectedValues: []string{"2024-03-11 00:00:00Z", "(null)", "2024-03-11 00:00:00Z"},
+		},
+		{
+			caseName: "truncate_to_day_in_time_zone",
+			dataType: newYork,
+			compute: func(arr arrow.Array) (arrow.Array, error) {
+				return TemporalTruncate(mem, arr, DayField)
+			},
+			expectedValues: []string{"2024-03-14 00:00:00-0400", "(null)", "2024-03-14 00:00:00-0400"},
+		},
+		{
+			caseName: "truncate_to_month_in_time_zone",
+			dataType: newYork,
+			compute: func(arr arrow.Array) (arrow.Array, error) {
+				return TemporalTruncate(mem, arr, MonthField)
+			},
+			expectedValues: []string{"2024-03-01 00:00:00-0500", "(null)", "2024-03-01 00:00:00-0500"},
+		},
+		{
+			caseName: "extract_day",
+			dataType: utc,
+			compute: func(arr arrow.Array) (arrow.Array, error) {
+				return TemporalExtract(mem, arr, DayField)
+			},
+			expectedValues: []string{"14", "(null)", "15"},
+		},
+		{
+			caseName: "extract_day_in_time_zone",
+			dataType: newYork,
+			compute: func(arr arrow.Array) (arrow.Array, error) {
+				return TemporalExtract(mem, arr, DayField)
+			},
+			expectedValues: []string{"14", "(null)", "14"},
+		},
+		{
+			caseName: "extract_weekday",
+			dataType: utc,
+			compute: func(arr arrow.Array) (arrow.Array, error) {
+				return TemporalExtract(mem, arr, WeekdayField)
+			},
+			expectedValues: []string{"4", "(null)", "5"},
+		},
+		{
+			caseName: "extract_week",
+			dataType: utc,
+			compute: func(arr arrow.Array) (arrow.Array, error) {
+				return TemporalExtract(mem, arr, WeekField)
+			},
+			expectedValues: []string{"11", "(null)", "11"},
+		},
+		{
+			caseName: "extract_year_from_date",
+			dataType: arrow.FixedWidthTypes.Date32,
+			values:   `["2024-03-14", null, "1999-12-31"]`,
+			compute: func(arr arrow.Array) (arrow.Array, error) {
+				return TemporalExtract(mem, arr, YearField)
+			},
+			expectedValues: []string{"2024", "(null)", "1999"},
+		},
+	}
+
+	for idx, tc := range testCases {
+		t.Run(fmt.Sprintf("case_%d:%s", idx, tc.caseName), func(t *testing.T) {
+			if tc.values == "" {
+				tc.values = values
+			}
+			arr := castTestArray(t, mem, tc.dataType, tc.values)
+			defer arr.Release()
+
+			result, err := tc.compute(arr)
+			if err != nil {
+				t.Fatalf("received unexpected error: %s", err)
+			}
+			defer result.Release()
+
+			if actual := arrayValueStrings(result); !slices.Equal(actual, tc.expectedValues) {
+				t.Errorf("expected values %v, got %v", tc.expectedValues, actual)
+			}
+		})
+	}
+
+}
+
+func TestTemporalAddAndDifference(t *testing.T) {
+	mem := memory.NewGoAllocator()
+
+	timestamps := castTestArray(t, mem, &arrow.TimestampType{Unit: arrow.Second, TimeZone: "UTC"}, `["2024-03-14T13:45:30Z", null, "2024-03-15T00:00:00Z"]`)
+	defer timestamps.Release()
+	durations := castTestArray(t, mem, arrow.FixedWidthTypes.Duration_ms, `[1500, 1000, null]`)
+	defer durations.Release()
+
+	added, err := TemporalAdd(mem, timestamps, durations, ArithmeticOptions{})
+	if err != nil {
+		t.Fatalf("received unexpected error: %s", err)
+	}
+	defer added.Release()
+	expectedType := &arrow.TimestampType{Unit: arrow.Millisecond, TimeZone: "UTC"}
+	if !arrow.TypeEqual(added.DataType(), expectedType) {
+		t.Errorf("expected type %s, got %s", expectedType, added.DataType())
+	}
+	expectedValues := []string{"2024-03-14 13:45:31.5Z", "(null)", "(null)"}
+	if actual := arrayValueStrings(added); !slices.Equal(actual, expectedValues) {
+		t.Errorf("expected values %v, got %v", expectedValues, actual)
+	}
+
+	shifted, err := TemporalAddScalar(mem, timestamps, -time.Hour, ArithmeticOptions{})
+	if err != nil {
+		t.Fatalf("received unexpected error: %s", err)
+	}
+	defer shifted.Release()
+	expectedValues = []string{"2024-03-14 12:45:30Z", "(null)", "2024-03-14 23:00:00Z"}
+	if actual := arrayValueStrings(shifted); !slices.Equal(actual, expectedValues) {
+		t.Errorf("expected values %v, got %v", expectedValues, actual)
+	}
+
+	dates := castTestArray(t, mem, arrow.FixedWidthTypes.Date32, `["2024-03-14", "2024-03-14", "2024-03-14"]`)
+	defer dates.Release()
+	difference, err := TemporalDifference(mem, timestamps, dates, ArithmeticOptions{})
+	if err != nil {
+		t.Fatalf("received unexpected error: %s", err)
+	}
+	defer difference.Release()
+	if !arrow.TypeEqual(difference.DataType(), arrow.FixedWidthTypes.Duration_s) {
+		t.Errorf("expected type %s, got %s", arrow.FixedWidthTypes.Duration_s, difference.DataType())
+	}
+	expectedValues = []string{"49530s", "(null)", "86400s"}
+	if actual := arrayValueStrings(difference); !slices.Equal(actual, expectedValues) {
+		t.Errorf("expected values %v, got %v", expectedValues, actual)
+	}
+}
+
+func TestTemporalConversions(t *testing.T) {
+	mem := memory.NewGoAllocator()
+
+	timestamps := castTestArray(t, mem, arrow.FixedWidthTypes.Timestamp_ms, `["2024-03-15T02:30:00.250Z", null]`)
+	defer timestamps.Release()
+
+	converted, err := TemporalToTimeZone(mem, timestamps, "America/New_York")
+	if err != nil {
+		t.Fatalf("received unexpected error: %s", err)
+	}
+	defer converted.Release()
+	hours, err := TemporalExtract(mem, converted, HourField)
+	if err != nil {
+		t.Fatalf("received unexpected error: %s", err)
+	}
+	defer hours.Release()
+	expectedValues := []string{"22", "(null)"}
+	if actual := arrayValueStrings(hours); !slices.Equal(actual, expectedValues) {
+		t.Errorf("expected values %v, got %v", expectedValues, actual)
+	}
+
+	_, err = TemporalToUnit(mem, timestamps, arrow.Second, CastOptions{})
+	if !errors.Is(err, ErrLossyCast) {
+		t.Errorf("expected error %v, got %v", ErrLossyCast, err)
+	}
+	seconds, err := TemporalToUnit(mem, timestamps, arrow.Second, CastOptions{AllowLossy: true})
+	if err != nil {
+		t.Fatalf("received unexpected error: %s", err)
+	}
+	defer seconds.Release()
+	expectedValues = []string{"2024-03-15 02:30:00Z", "(null)"}
+	if actual := arrayValueStrings(seconds); !slices.Equal(actual, expectedValues) {
+		t.Errorf("expected values %v, got %v", expectedValues, actual)
+	}
+}
+
+func TestTemporalKernelsErrors(t *testing.T) {
+	mem := memory.NewGoAllocator()
+
+	timestamps := castTestArray(t, mem, arrow.FixedWidthTypes.Timestamp_s, `[0]`)
+	defer timestamps.Release()
+	ints := castTestArray(t, mem, arrow.PrimitiveTypes.Int64, `[1]`)
+	defer ints.Release()
+
+	_, err := TemporalTruncate(mem, timestamps, WeekdayField)
+	if !errors.Is(err, ErrUnsupportedDataType) {
+		t.Errorf("expected error %v, got %v", ErrUnsupportedDataType, err)
+	}
+	_, err = TemporalExtract(mem, ints, YearField)
+	if !errors.Is(err, ErrUnsupportedDataType) {
+		t.Errorf("expected error %v, got %v", ErrUnsupportedDataType, err)
+	}
+	_, err = TemporalAdd(mem, timestamps, ints, ArithmeticOptions{})
+	if !errors.Is(err, ErrUnsupportedDataType) {
+		t.Errorf("expected error %v, got %v", ErrUnsupportedDataType, err)
+	}
+	_, err = TemporalDifference(mem, timestamps, ints, ArithmeticOptions{})
+	if !errors.Is(err, ErrUnsupportedDataType) {
+		t.Errorf("expected error %v, got %v", ErrUnsupportedDataType, err)
+	}
+	// scaling the seconds to the nanoseconds of the duration overflows
+	large := castTestArray(t, mem, arrow.FixedWidthTypes.Timestamp_s, `[10000000000]`)
+	defer large.Release()
+	_, err = TemporalAddScalar(mem, large, time.Nanosecond, ArithmeticOptions{CheckOverflow: true})
+	if !errors.Is(err, ErrOverflow) {
+		t.Errorf("expected error %v, got %v", ErrOverflow, err)
+	}
+	_, err = TemporalToTimeZone(mem, timestamps, "Not/A_Zone")
+	if err == nil {
+		t.Errorf("expected an error for an unknown time zone")
+	}
+}
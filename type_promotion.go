@@ -0,0 +1,220 @@
+package arrowops
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/alekLukanen/errs"
+	"github.com/apache/arrow/go/v17/arrow"
+	"github.com/apache/arrow/go/v17/arrow/array"
+)
+
+type numericKind int
+
+const (
+	signedNumeric numericKind = iota
+	unsignedNumeric
+	floatNumeric
+)
+
+type numericValue struct {
+	kind numericKind
+	i    int64
+	u    uint64
+	f    float64
+}
+
+type temporalKind int
+
+const (
+	instantTemporal temporalKind = iota
+	durationTemporal
+	timeOfDayTemporal
+)
+
+/*
+A temporal value split into whole seconds and the remaining nanoseconds
+so values with different units can be compared without overflowing.
+*/
+type temporalValue struct {
+	kind    temporalKind
+	seconds int64
+	nanos   int64
+}
+
+/*
+Compares two values in the same way as compareArrayValues but allows the
+arrays to have different, compatible, data types. Integers are compared
+exactly regardless of width or signedness. When an integer is compared
+with a float both values are converted to float64, so integers larger
+than 2^53 may lose precision.
+*/
+func comparePromotedArrayValues(a1, a2 arrow.Array, i1, i2 int) (int, error) {
+	if arrow.TypeEqual(a1.DataType(), a2.DataType()) {
+		return compareArrayValues(a1, a2, i1, i2)
+	}
+
+	if a1.IsNull(i1) && a2.IsNull(i2) {
+		return 0, nil
+	} else if a1.IsNull(i1) {
+		return -1, nil
+	} else if a2.IsNull(i2) {
+		return 1, nil
+	}
+
+	if v1, ok := numericArrayValue(a1, i1); ok {
+		if v2, ok := numericArrayValue(a2, i2); ok {
+			return compareNumericValues(v1, v2), nil
+		}
+	}
+	if v1, ok := temporalArrayValue(a1, i1); ok {
+		if v2, ok := temporalArrayValue(a2, i2); ok && v1.kind == v2.kind {
+			if n := cmp.Compare(v1.seconds, v2.seconds); n != 0 {
+				return n, nil
+			}
+			return cmp.Compare(v1.nanos, v2.nanos), nil
+		}
+	}
+	if v1, ok := stringArrayValue(a1, i1); ok {
+		if v2, ok := stringArrayValue(a2, i2); ok {
+			return cmp.Compare(v1, v2), nil
+		}
+	}
+	if v1, ok := binaryArrayValue(a1, i1); ok {
+		if v2, ok := binaryArrayValue(a2, i2); ok {
+			return bytes.Compare(v1, v2), nil
+		}
+	}
+
+	return 0, errs.NewStackError(fmt.Errorf("%w| %s and %s can not be promoted to a common type", ErrDataTypesNotEqual, a1.DataType(), a2.DataType()))
+}
+
+func compareNumericValues(v1, v2 numericValue) int {
+	switch {
+	case v1.kind == floatNumeric || v2.kind == floatNumeric:
+		return cmp.Compare(v1.float64(), v2.float64())
+	case v1.kind == signedNumeric && v2.kind == signedNumeric:
+		return cmp.Compare(v1.i, v2.i)
+	case v1.kind == unsignedNumeric && v2.kind == unsignedNumeric:
+		return cmp.Compare(v1.u, v2.u)
+	case v1.kind == signedNumeric:
+		if v1.i < 0 {
+			return -1
+		}
+		return cmp.Compare(uint64(v1.i), v2.u)
+	default:
+		if v2.i < 0 {
+			return 1
+		}
+		return cmp.Compare(v1.u, uint64(v2.i))
+	}
+}
+
+func (v numericValue) float64() float64 {
+	switch v.kind {
+	case signedNumeric:
+		return float64(v.i)
+	case unsignedNumeric:
+		return float64(v.u)
+	default:
+		return v.f
+	}
+}
+
+func (v numericValue) String() string {
+	switch v.kind {
+	case signedNumeric:
+		return strconv.FormatInt(v.i, 10)
+	case unsignedNumeric:
+		return strconv.FormatUint(v.u, 10)
+	default:
+		return strconv.FormatFloat(v.f, 'g', -1, 64)
+	}
+}
+
+func numericArrayValue(arr arrow.Array, i int) (numericValue, bool) {
+	switch a := arr.(type) {
+	case *array.Int8:
+		return numericValue{kind: signedNumeric, i: int64(a.Value(i))}, true
+	case *array.Int16:
+		return numericValue{kind: signedNumeric, i: int64(a.Value(i))}, true
+	case *array.Int32:
+		return numericValue{kind: signedNumeric, i: int64(a.Value(i))}, true
+	case *array.Int64:
+		return numericValue{kind: signedNumeric, i: a.Value(i)}, true
+	case *array.Uint8:
+		return numericValue{kind: unsignedNumeric, u: uint64(a.Value(i))}, true
+	case *array.Uint16:
+		return numericValue{kind: unsignedNumeric, u: uint64(a.Value(i))}, true
+	case *array.Uint32:
+		return numericValue{kind: unsignedNumeric, u: uint64(a.Value(i))}, true
+	case *array.Uint64:
+		return numericValue{kind: unsignedNumeric, u: a.Value(i)}, true
+	case *array.Float16:
+		return numericValue{kind: floatNumeric, f: float64(a.Value(i).Float32())}, true
+	case *array.Float32:
+		return numericValue{kind: floatNumeric, f: float64(a.Value(i))}, true
+	case *array.Float64:
+		return numericValue{kind: floatNumeric, f: a.Value(i)}, true
+	default:
+		return numericValue{}, false
+	}
+}
+
+func newTemporalValue(kind temporalKind, value int64, unit arrow.TimeUnit) temporalValue {
+	nanosPerUnit := int64(unit.Multiplier())
+	unitsPerSecond := int64(time.Second) / nanosPerUnit
+	return temporalValue{
+		kind:    kind,
+		seconds: value / unitsPerSecond,
+		nanos:   (value % unitsPerSecond) * nanosPerUnit,
+	}
+}
+
+func temporalArrayValue(arr arrow.Array, i int) (temporalValue, bool) {
+	switch a := arr.(type) {
+	case *array.Timestamp:
+		unit := a.DataType().(*arrow.TimestampType).Unit
+		return newTemporalValue(instantTemporal, int64(a.Value(i)), unit), true
+	case *array.Date32:
+		return temporalValue{kind: instantTemporal, seconds: int64(a.Value(i)) * 86400}, true
+	case *array.Date64:
+		return newTemporalValue(instantTemporal, int64(a.Value(i)), arrow.Millisecond), true
+	case *array.Duration:
+		unit := a.DataType().(*arrow.DurationType).Unit
+		return newTemporalValue(durationTemporal, int64(a.Value(i)), unit), true
+	case *array.Time32:
+		unit := a.DataType().(*arrow.Time32Type).Unit
+		return newTemporalValue(timeOfDayTemporal, int64(a.Value(i)), unit), true
+	case *array.Time64:
+		unit := a.DataType().(*arrow.Time64Type).Unit
+		return newTemporalValue(timeOfDayTemporal, int64(a.Value(i)), unit), true
+	default:
+		return temporalValue{}, false
+	}
+}
+
+func stringArrayValue(arr arrow.Array, i int) (string, bool) {
+	switch a := arr.(type) {
+	case *array.String:
+		return a.Value(i), true
+	case *array.LargeString:
+		return a.Value(i), true
+	default:
+		return "", false
+	}
+}
+
+func binaryArrayValue(arr arrow.Array, i int) ([]byte, bool) {
+	switch a := arr.(type) {
+	case *array.Binary:
+		return a.Value(i), true
+	case *array.LargeBinary:
+		return a.Value(i), true
+	default:
+		return nil, false
+	}
+}
@@ -0,0 +1,22 @@
+package arrowops
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/apache/arrow/go/v17/arrow"
+	"github.com/apache/arrow/go/v17/arrow/array"
+	"github.com/apache/arrow/go/v17/arrow/memory"
+)
+
+/*
+Builds a record with the schema from a JSON array of rows, for example
+[{"a": 1, "b": "x"}, {"a": null, "b": "y"}].
+*/
+func recordFromJSON(t *testing.T, mem *memory.GoAllocator, schema *arrow.Schema, rows string) arrow.Record {
+	record, _, err := array.RecordFromJSON(mem, schema, strings.NewReader(rows))
+	if err != nil {
+		t.Fatalf("received unexpected error: %s", err)
+	}
+	return record
+}
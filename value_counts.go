@@ -0,0 +1,130 @@
+package arrowops
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+
+	"github.com/alekLukanen/errs"
+	"github.com/apache/arrow/go/v17/arrow"
+	"github.com/apache/arrow/go/v17/arrow/array"
+	"github.com/apache/arrow/go/v17/arrow/memory"
+)
+
+const (
+	ValueCountsValueColumn = "value"
+	ValueCountsCountColumn = "count"
+)
+
+/*
+Options used by ValueCountsWithOptions.
+*/
+type ValueCountsOptions struct {
+	// sort the values by count in descending order, values with
+	// equal counts stay in the order they first appear
+	SortByCount bool
+}
+
+/*
+Returns the distinct values of the array in the order they first appear. Null
+values are treated as equal so a single null is kept when the array has any.
+*/
+func Unique(mem *memory.GoAllocator, arr arrow.Array) (arrow.Array, error) {
+	record := valuesRecord(arr)
+	defer record.Release()
+
+	groups, err := hashGroupValues(record)
+	if err != nil {
+		return nil, err
+	}
+	return takeGroupValues(mem, record, groups.firstRows)
+}
+
+/*
+Counts the number of times each distinct value appears in the array. The
+returned record has a "value" column with the distinct values, in the order
+they first appear, and an INT64 "count" column. Null values are counted
+together.
+*/
+func ValueCounts(mem *memory.GoAllocator, arr arrow.Array) (arrow.Record, error) {
+	return ValueCountsWithOptions(mem, arr, ValueCountsOptions{})
+}
+
+/*
+Same as ValueCounts but the values can be sorted by count with the options.
+*/
+func ValueCountsWithOptions(mem *memory.GoAllocator, arr arrow.Array, opts ValueCountsOptions) (arrow.Record, error) {
+	record := valuesRecord(arr)
+	defer record.Release()
+
+	groups, err := hashGroupValues(record)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make([]int64, groups.numGroups())
+	for _, group := range groups.groupIDs {
+		counts[group]++
+	}
+	order := make([]int, groups.numGroups())
+	for i := range order {
+		order[i] = i
+	}
+	if opts.SortByCount {
+		slices.SortStableFunc(order, func(a, b int) int {
+			return cmp.Compare(counts[b], counts[a])
+		})
+	}
+
+	rows := make([]uint32, len(order))
+	countBuilder := array.NewInt64Builder(mem)
+	defer countBuilder.Release()
+	countBuilder.Reserve(len(order))
+	for i, group := range order {
+		rows[i] = groups.firstRows[group]
+		countBuilder.Append(counts[group])
+	}
+	countArray := countBuilder.NewArray()
+	defer countArray.Release()
+
+	values, err := takeGroupValues(mem, record, rows)
+	if err != nil {
+		return nil, err
+	}
+	defer values.Release()
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: ValueCountsValueColumn, Type: arr.DataType(), Nullable: true},
+		{Name: ValueCountsCountColumn, Type: arrow.PrimitiveTypes.Int64},
+	}, nil)
+	return array.NewRecord(schema, []arrow.Array{values, countArray}, int64(len(order))), nil
+}
+
+/*
+Wraps the array in a record with a single "value" column so its values can be
+grouped with hashGroupRows and taken with takeRecordRows.
+*/
+func valuesRecord(arr arrow.Array) arrow.Record {
+	schema := arrow.NewSchema([]arrow.Field{{Name: ValueCountsValueColumn, Type: arr.DataType(), Nullable: true}}, nil)
+	return array.NewRecord(schema, []arrow.Array{arr}, int64(arr.Len()))
+}
+
+func hashGroupValues(record arrow.Record) (*rowGroups, error) {
+	groups, err := hashGroupRows(record, []string{ValueCountsValueColumn})
+	if err != nil {
+		return nil, errs.Wrap(err, fmt.Errorf("failed to group values of type %s", record.Column(0).DataType()))
+	}
+	return groups, nil
+}
+
+func takeGroupValues(mem *memory.GoAllocator, record arrow.Record, rows []uint32) (arrow.Array, error) {
+	taken, err := takeRecordRows(mem, record, rows)
+	if err != nil {
+		return nil, errs.Wrap(err, fmt.Errorf("failed to take %d values from array", len(rows)))
+	}
+	defer taken.Release()
+
+	values := taken.Column(0)
+	values.Retain()
+	return values, nil
+}
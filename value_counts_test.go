@@ -0,0 +1,115 @@
+package arrowops
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/apache/arrow/go/v17/arrow"
+	"github.com/apache/arrow/go/v17/arrow/memory"
+)
+
+func TestUniqueAndValueCounts(t *testing.T) {
+
+	mem := memory.NewGoAllocator()
+
+	testCases := []struct {
+		caseName       string
+		dataType       arrow.DataType
+		values         string
+		opts           ValueCountsOptions
+		expectedUnique []string
+		expectedCounts []string
+	}{
+		{
+			caseName:       "strings_with_nulls",
+			dataType:       arrow.BinaryTypes.String,
+			values:         `["b", null, "a", "b", null, "b"]`,
+			expectedUnique: []string{"b", "(null)", "a"},
+			expectedCounts: []string{"b,3", "(null),2", "a,1"},
+		},
+		{
+			caseName:       "sorted_by_count",
+			dataType:       arrow.PrimitiveTypes.Int32,
+			values:         `[1, 2, 3, 2, 3, 3, 4]`,
+			opts:           ValueCountsOptions{SortByCount: true},
+			expectedUnique: []string{"1", "2", "3", "4"},
+			expectedCounts: []string{"3,3", "2,2", "1,1", "4,1"},
+		},
+		{
+			caseName:       "floats",
+			dataType:       arrow.PrimitiveTypes.Float64,
+			values:         `[0.5, 1.5, 0.5]`,
+			expectedUnique: []string{"0.5", "1.5"},
+			expectedCounts: []string{"0.5,2", "1.5,1"},
+		},
+		{
+			caseName:       "booleans",
+			dataType:       arrow.FixedWidthTypes.Boolean,
+			values:         `[true, false, true, true]`,
+			opts:           ValueCountsOptions{SortByCount: true},
+			expectedUnique: []string{"true", "false"},
+			expectedCounts: []string{"true,3", "false,1"},
+		},
+		{
+			caseName:       "large_strings",
+			dataType:       arrow.BinaryTypes.LargeString,
+			values:         `["x", "y", "x"]`,
+			expectedUnique: []string{"x", "y"},
+			expectedCounts: []string{"x,2", "y,1"},
+		},
+		{
+			caseName:       "empty",
+			dataType:       arrow.FixedWidthTypes.Date32,
+			values:         `[]`,
+			expectedUnique: []string{},
+			expectedCounts: []string{},
+		},
+	}
+
+	for idx, tc := range testCases {
+		t.Run(fmt.Sprintf("case_%d:%s", idx, tc.caseName), func(t *testing.T) {
+			arr := castTestArray(t, mem, tc.dataType, tc.values)
+			defer arr.Release()
+
+			unique, err := Unique(mem, arr)
+			if err != nil {
+				t.Fatalf("received unexpected error: %s", err)
+			}
+			defer unique.Release()
+			if !arrow.TypeEqual(unique.DataType(), tc.dataType) {
+				t.Errorf("expected type %s, got %s", tc.dataType, unique.DataType())
+			}
+			if actual := arrayValueStrings(unique); !slices.Equal(actual, tc.expectedUnique) {
+				t.Errorf("expected unique values %v, got %v", tc.expectedUnique, actual)
+			}
+
+			counts, err := ValueCountsWithOptions(mem, arr, tc.opts)
+			if err != nil {
+				t.Fatalf("received unexpected error: %s", err)
+			}
+			defer counts.Release()
+			if actual := recordRowStrings(counts); !slices.Equal(actual, tc.expectedCounts) {
+				t.Errorf("expected counts %v, got %v", tc.expectedCounts, actual)
+			}
+		})
+	}
+
+}
+
+func TestValueCountsErrors(t *testing.T) {
+	mem := memory.NewGoAllocator()
+
+	arr := castTestArray(t, mem, arrow.ListOf(arrow.PrimitiveTypes.Int32), `[[1]]`)
+	defer arr.Release()
+
+	_, err := Unique(mem, arr)
+	if !errors.Is(err, ErrUnsupportedDataType) {
+		t.Errorf("expected error %v, got %v", ErrUnsupportedDataType, err)
+	}
+	_, err = ValueCounts(mem, arr)
+	if !errors.Is(err, ErrUnsupportedDataType) {
+		t.Errorf("expected error %v, got %v", ErrUnsupportedDataType, err)
+	}
+}
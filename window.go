@@ -0,0 +1,385 @@
+package arrowops
+
+import (
+	"fmt"
+
+	"github.com/alekLukanen/errs"
+	"github.com/apache/arrow/go/v17/arrow"
+	"github.com/apache/arrow/go/v17/arrow/array"
+	"github.com/apache/arrow/go/v17/arrow/memory"
+)
+
+/*
+The rows a window function is computed over, like PARTITION BY ... ORDER BY ...
+in SQL. Rows with equal values in the PartitionBy columns form a partition, or
+the whole record is a single partition when PartitionBy is empty, and the rows
+of each partition are ordered by the OrderBy keys.
+*/
+type WindowSpec struct {
+	PartitionBy []string
+	OrderBy     []SortKey
+}
+
+type windowFunctionKind int
+
+const (
+	rowNumberWindow windowFunctionKind = iota
+	rankWindow
+	denseRankWindow
+	lagWindow
+	leadWindow
+	firstValueWindow
+	runningSumWindow
+)
+
+func (k windowFunctionKind) String() string {
+	switch k {
+	case rowNumberWindow:
+		return "row_number"
+	case rankWindow:
+		return "rank"
+	case denseRankWindow:
+		return "dense_rank"
+	case lagWindow:
+		return "lag"
+	case leadWindow:
+		return "lead"
+	case firstValueWindow:
+		return "first_value"
+	default:
+		return "running_sum"
+	}
+}
+
+/*
+A function computed for each row from the rows of its partition. The result
+column is named after the function, for example "row_number" or "lag(amount, 1)",
+unless a name is provided with As.
+*/
+type WindowFunction struct {
+	kind   windowFunctionKind
+	column string
+	offset int
+	name   string
+}
+
+/*
+The position of the row in its partition as an INT64, starting at one.
+*/
+func RowNumber() WindowFunction {
+	return WindowFunction{kind: rowNumberWindow}
+}
+
+/*
+The position of the first row in the partition with the same OrderBy values as
+the row, as an INT64 starting at one. Rows with equal values have the same rank
+and leave a gap in the ranks after them.
+*/
+func Rank() WindowFunction {
+	return WindowFunction{kind: rankWindow}
+}
+
+/*
+The number of distinct OrderBy values in the partition up to and including the
+row's values, as an INT64 starting at one. Unlike Rank there are no gaps.
+*/
+func DenseRank() WindowFunction {
+	return WindowFunction{kind: denseRankWindow}
+}
+
+/*
+The value of the column offset rows before the row in its partition, or null
+when there is no such row.
+*/
+func Lag(column string, offset int) WindowFunction {
+	return WindowFunction{kind: lagWindow, column: column, offset: offset}
+}
+
+/*
+The value of the column offset rows after the row in its partition, or null
+when there is no such row.
+*/
+func Lead(column string, offset int) WindowFunction {
+	return WindowFunction{kind: leadWindow, column: column, offset: offset}
+}
+
+/*
+The value of the column in the first row of the partition.
+*/
+func FirstValue(column string) WindowFunction {
+	return WindowFunction{kind: firstValueWindow, column: column}
+}
+
+/*
+The sum of the values of a numeric column from the first row of the partition
+up to and including the row and its peers, the rows with equal order values,
+like SUM with an ORDER BY in SQL. Peer rows have the same sum, so without order
+keys every row has the sum of the partition. The sum has the same types as Sum,
+null values are ignored and the sum is null until the first value.
+*/
+func RunningSum(column string) WindowFunction {
+	return WindowFunction{kind: runningSumWindow, column: column}
+}
+
+/*
+Returns a copy of the window function with the result column named name.
+*/
+func (f WindowFunction) As(name string) WindowFunction {
+	f.name = name
+	return f
+}
+
+func (f WindowFunction) Name() string {
+	switch {
+	case f.name != "":
+		return f.name
+	case f.kind == lagWindow || f.kind == leadWindow:
+		return fmt.Sprintf("%s(%s, %d)", f.kind, f.column, f.offset)
+	case f.column != "":
+		return fmt.Sprintf("%s(%s)", f.kind, f.column)
+	default:
+		return f.kind.String()
+	}
+}
+
+/*
+The rows of a record in window order along with the
+boundaries of the partitions and of the peer rows.
+*/
+type windowFrame struct {
+	// the rows of the record sorted by partition and order
+	rows []uint32
+	// the position in rows of the first row of each row's partition
+	partitionStarts []int
+	// the position in rows of the first row with the same order values
+	peerStarts []int
+}
+
+/*
+Computes the window functions and returns the record with a column appended for
+each function. The rows are sorted by the partition and order keys, using the
+same stable sort as SortRecordIndices, but the returned record keeps the order of
+the input record.
+*/
+func WindowRecord(mem *memory.GoAllocator, record arrow.Record, spec WindowSpec, functions ...WindowFunction) (arrow.Record, error) {
+	record.Retain()
+	defer record.Release()
+
+	frame, err := newWindowFrame(record, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := append(make([]arrow.Field, 0, int(record.NumCols())+len(functions)), record.Schema().Fields()...)
+	columns := append(make([]arrow.Array, 0, int(record.NumCols())+len(functions)), record.Columns()...)
+	results := make([]arrow.Array, 0, len(functions))
+	defer func() {
+		releaseArrays(results)
+	}()
+	for _, function := range functions {
+		result, err := frame.compute(mem, record, function)
+		if err != nil {
+			return nil, errs.Wrap(err, fmt.Errorf("failed to compute window function %s", function.Name()))
+		}
+		results = append(results, result)
+		fields = append(fields, arrow.Field{
+			Name:     function.Name(),
+			Type:     result.DataType(),
+			Nullable: function.kind != rowNumberWindow && function.kind != rankWindow && function.kind != denseRankWindow,
+		})
+		columns = append(columns, result)
+	}
+
+	metadata := record.Schema().Metadata()
+	return array.NewRecord(arrow.NewSchema(fields, &metadata), columns, record.NumRows()), nil
+}
+
+func newWindowFrame(record arrow.Record, spec WindowSpec) (*windowFrame, error) {
+	sortKeys := append(SortKeysFromColumns(spec.PartitionBy...), spec.OrderBy...)
+	numRows := int(record.NumRows())
+
+	frame := &windowFrame{
+		rows:            make([]uint32, numRows),
+		partitionStarts: make([]int, numRows),
+		peerStarts:      make([]int, numRows),
+	}
+	if len(sortKeys) == 0 {
+		// every row is in the same partition and is a peer of every other row
+		for i := range frame.rows {
+			frame.rows[i] = uint32(i)
+		}
+		return frame, nil
+	}
+
+	rows, err := sortRowIndices(record, sortKeys)
+	if err != nil {
+		return nil, errs.Wrap(err, fmt.Errorf("failed to sort record by the window keys"))
+	}
+	frame.rows = rows
+
+	var partitionComparator, orderComparator *RowComparator
+	if len(spec.PartitionBy) > 0 {
+		partitionComparator, err = NewRowComparator(record.Schema(), record.Schema(), SortKeysFromColumns(spec.PartitionBy...))
+		if err != nil {
+			return nil, errs.Wrap(err, fmt.Errorf("failed to create comparator for partition keys %v", spec.PartitionBy))
+		}
+	}
+	if len(spec.OrderBy) > 0 {
+		orderComparator, err = NewRowComparator(record.Schema(), record.Schema(), spec.OrderBy)
+		if err != nil {
+			return nil, errs.Wrap(err, fmt.Errorf("failed to create comparator for order keys"))
+		}
+	}
+
+	for i := 1; i < numRows; i++ {
+		previous, current := int(rows[i-1]), int(rows[i])
+		if partitionComparator != nil && !partitionComparator.Equal(record, record, previous, current) {
+			frame.partitionStarts[i] = i
+			frame.peerStarts[i] = i
+			continue
+		}
+		frame.partitionStarts[i] = frame.partitionStarts[i-1]
+		if orderComparator != nil && !orderComparator.Equal(record, record, previous, current) {
+			frame.peerStarts[i] = i
+		} else {
+			frame.peerStarts[i] = frame.peerStarts[i-1]
+		}
+	}
+	return frame, nil
+}
+
+func (f *windowFrame) compute(mem *memory.GoAllocator, record arrow.Record, function WindowFunction) (arrow.Array, error) {
+	var column arrow.Array
+	if function.kind >= lagWindow {
+		columnIdxs := record.Schema().FieldIndices(function.column)
+		if len(columnIdxs) == 0 {
+			return nil, errs.NewStackError(fmt.Errorf("%w| column name: %s", ErrColumnNotFound, function.column))
+		}
+		column = record.Column(columnIdxs[0])
+	}
+
+	switch function.kind {
+	case rowNumberWindow, rankWindow, denseRankWindow:
+		return f.ranks(mem, function.kind), nil
+	case lagWindow, leadWindow:
+		if function.offset < 0 {
+			return nil, errs.NewStackError(fmt.Errorf("%w| offset %d must not be negative", ErrIndexOutOfBounds, function.offset))
+		}
+		offset := function.offset
+		if function.kind == lagWindow {
+			offset = -offset
+		}
+		return f.shifted(mem, column, offset)
+	case firstValueWindow:
+		return f.firstValues(mem, column)
+	default:
+		return f.runningSums(mem, column)
+	}
+}
+
+func (f *windowFrame) ranks(mem *memory.GoAllocator, kind windowFunctionKind) arrow.Array {
+	values := make([]int64, len(f.rows))
+	var denseRank int64
+	for i, row := range f.rows {
+		switch kind {
+		case rowNumberWindow:
+			values[row] = int64(i - f.partitionStarts[i] + 1)
+		case rankWindow:
+			values[row] = int64(f.peerStarts[i] - f.partitionStarts[i] + 1)
+		default:
+			if i == f.partitionStarts[i] {
+				denseRank = 0
+			}
+			if i == f.peerStarts[i] {
+				denseRank++
+			}
+			values[row] = denseRank
+		}
+	}
+
+	b := array.NewInt64Builder(mem)
+	defer b.Release()
+	b.AppendValues(values, nil)
+	return b.NewArray()
+}
+
+/*
+Takes the value offset rows away from each row in the same partition.
+*/
+func (f *windowFrame) shifted(mem *memory.GoAllocator, column arrow.Array, offset int) (arrow.Array, error) {
+	indices := make([]uint32, len(f.rows))
+	valid := make([]bool, len(f.rows))
+	for i, row := range f.rows {
+		source := i + offset
+		if source < 0 || source >= len(f.rows) || f.partitionStarts[source] != f.partitionStarts[i] {
+			continue
+		}
+		indices[row] = f.rows[source]
+		valid[row] = true
+	}
+	return takeWindowValues(mem, column, indices, valid)
+}
+
+func (f *windowFrame) firstValues(mem *memory.GoAllocator, column arrow.Array) (arrow.Array, error) {
+	indices := make([]uint32, len(f.rows))
+	for i, row := range f.rows {
+		indices[row] = f.rows[f.partitionStarts[i]]
+	}
+	return takeWindowValues(mem, column, indices, nil)
+}
+
+func takeWindowValues(mem *memory.GoAllocator, column arrow.Array, indices []uint32, valid []bool) (arrow.Array, error) {
+	b := array.NewUint32Builder(mem)
+	defer b.Release()
+	b.AppendValues(indices, valid)
+	indicesArray := b.NewUint32Array()
+	defer indicesArray.Release()
+	return TakeArray(mem, column, indicesArray)
+}
+
+func (f *windowFrame) runningSums(mem *memory.GoAllocator, column arrow.Array) (arrow.Array, error) {
+	kind, ok := numericKindOf(column.DataType())
+	if !ok {
+		return nil, errs.NewStackError(fmt.Errorf("%w| can not sum %s", ErrUnsupportedDataType, column.DataType()))
+	}
+
+	// the running sum at each position in window order
+	running := make([]numericValue, len(f.rows))
+	hasValue := make([]bool, len(f.rows))
+	for i, row := range f.rows {
+		if i != f.partitionStarts[i] {
+			running[i] = running[i-1]
+			hasValue[i] = hasValue[i-1]
+		}
+		if column.IsValid(int(row)) {
+			// only the field of the column's kind is set
+			value, _ := numericArrayValue(column, int(row))
+			running[i].i += value.i
+			running[i].u += value.u
+			running[i].f += value.f
+			hasValue[i] = true
+		}
+	}
+
+	// store the sums with a sum aggregator that has one group for each row,
+	// every peer row gets the running sum at the last row of its peers
+	sums := &sumAggregator{kind: kind}
+	sums.resize(len(f.rows))
+	lastPeer := len(f.rows) - 1
+	for i := len(f.rows) - 1; i >= 0; i-- {
+		if i+1 < len(f.rows) && f.peerStarts[i+1] != f.peerStarts[i] {
+			lastPeer = i
+		}
+		row := f.rows[i]
+		switch kind {
+		case signedNumeric:
+			sums.ints[row] = running[lastPeer].i
+		case unsignedNumeric:
+			sums.uints[row] = running[lastPeer].u
+		default:
+			sums.floats[row] = running[lastPeer].f
+		}
+		sums.valid[row] = hasValue[lastPeer]
+	}
+	return sums.flush(mem, len(f.rows))
+}
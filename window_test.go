@@ -0,0 +1,150 @@
+package arrowops
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/apache/arrow/go/v17/arrow"
+	"github.com/apache/arrow/go/v17/arrow/memory"
+)
+
+func TestWindowRecord(t *testing.T) {
+
+	mem := memory.NewGoAllocator()
+
+	record := recordFromJSON(t, mem, arrow.NewSchema(
+		[]arrow.Field{
+			{Name: "dept", Type: arrow.BinaryTypes.String},
+			{Name: "salary", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+			{Name: "id", Type: arrow.PrimitiveTypes.Int32},
+		}, nil), `[
+			{"dept": "a", "salary": 10, "id": 0},
+			{"dept": "b", "salary": 20, "id": 1},
+			{"dept": "a", "salary": 30, "id": 2},
+			{"dept": "a", "salary": 10, "id": 3},
+			{"dept": "b", "salary": null, "id": 4},
+			{"dept": "a", "salary": 40, "id": 5}
+		]`,
+	)
+	defer record.Release()
+
+	testCases := []struct {
+		caseName        string
+		spec            WindowSpec
+		functions       []WindowFunction
+		expectedColumns []string
+		expectedRows    []string
+	}{
+		{
+			caseName: "partitioned_and_ordered",
+			spec: WindowSpec{
+				PartitionBy: []string{"dept"},
+				OrderBy:     SortKeysFromColumns("salary"),
+			},
+			functions: []WindowFunction{
+				RowNumber(), Rank(), DenseRank(), Lag("salary", 1), Lead("salary", 1), FirstValue("id"), RunningSum("salary"),
+			},
+			expectedColumns: []string{
+				"dept", "salary", "id", "row_number", "rank", "dense_rank",
+				"lag(salary, 1)", "lead(salary, 1)", "first_value(id)", "running_sum(salary)",
+			},
+			expectedRows: []string{
+				// the rows with salary 10 are peers so they have the same running sum
+				"a,10,0,1,1,1,(null),10,0,20",
+				"b,20,1,2,2,2,(null),(null),4,20",
+				"a,30,2,3,3,2,10,40,0,50",
+				"a,10,3,2,1,1,10,30,0,20",
+				"b,(null),4,1,1,1,(null),20,4,(null)",
+				"a,40,5,4,4,3,30,(null),0,90",
+			},
+		},
+		{
+			caseName: "descending_order_without_partitions",
+			spec: WindowSpec{
+				OrderBy: []SortKey{{Column: "salary", Descending: true, NullsLast: true}},
+			},
+			functions:       []WindowFunction{RowNumber().As("position"), Lag("id", 2)},
+			expectedColumns: []string{"dept", "salary", "id", "position", "lag(id, 2)"},
+			expectedRows: []string{
+				"a,10,0,4,2",
+				"b,20,1,3,5",
+				"a,30,2,2,(null)",
+				"a,10,3,5,1",
+				"b,(null),4,6,0",
+				"a,40,5,1,(null)",
+			},
+		},
+		{
+			caseName:        "single_window",
+			functions:       []WindowFunction{RowNumber(), Rank(), RunningSum("id")},
+			expectedColumns: []string{"dept", "salary", "id", "row_number", "rank", "running_sum(id)"},
+			expectedRows: []string{
+				// without order keys every row is a peer of every other row
+				"a,10,0,1,1,15",
+				"b,20,1,2,1,15",
+				"a,30,2,3,1,15",
+				"a,10,3,4,1,15",
+				"b,(null),4,5,1,15",
+				"a,40,5,6,1,15",
+			},
+		},
+	}
+
+	for idx, tc := range testCases {
+		t.Run(fmt.Sprintf("case_%d:%s", idx, tc.caseName), func(t *testing.T) {
+			result, err := WindowRecord(mem, record, tc.spec, tc.functions...)
+			if err != nil {
+				t.Fatalf("received unexpected error: %s", err)
+			}
+			defer result.Release()
+
+			columns := make([]string, result.NumCols())
+			for i := range columns {
+				columns[i] = result.ColumnName(i)
+			}
+			if !slices.Equal(columns, tc.expectedColumns) {
+				t.Errorf("expected columns %v, got %v", tc.expectedColumns, columns)
+			}
+			if actual := recordRowStrings(result); !slices.Equal(actual, tc.expectedRows) {
+				t.Errorf("expected rows %v, got %v", tc.expectedRows, actual)
+			}
+		})
+	}
+
+}
+
+func TestWindowRecordErrors(t *testing.T) {
+	mem := memory.NewGoAllocator()
+
+	record := recordFromJSON(t, mem, arrow.NewSchema(
+		[]arrow.Field{
+			{Name: "dept", Type: arrow.BinaryTypes.String},
+			{Name: "id", Type: arrow.PrimitiveTypes.Int32},
+		}, nil),
+		`[{"dept": "a", "id": 0}]`,
+	)
+	defer record.Release()
+
+	testCases := []struct {
+		caseName    string
+		spec        WindowSpec
+		function    WindowFunction
+		expectedErr error
+	}{
+		{caseName: "partition_not_found", spec: WindowSpec{PartitionBy: []string{"z"}}, function: RowNumber(), expectedErr: ErrColumnNotFound},
+		{caseName: "column_not_found", function: Lag("z", 1), expectedErr: ErrColumnNotFound},
+		{caseName: "negative_offset", function: Lead("id", -1), expectedErr: ErrIndexOutOfBounds},
+		{caseName: "sum_of_strings", function: RunningSum("dept"), expectedErr: ErrUnsupportedDataType},
+	}
+
+	for idx, tc := range testCases {
+		t.Run(fmt.Sprintf("case_%d:%s", idx, tc.caseName), func(t *testing.T) {
+			_, err := WindowRecord(mem, record, tc.spec, tc.function)
+			if !errors.Is(err, tc.expectedErr) {
+				t.Errorf("expected error %v, got %v", tc.expectedErr, err)
+			}
+		})
+	}
+}